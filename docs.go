@@ -39,6 +39,7 @@ Toggles control the following functionality:
     c - toggle custom environment (to reduce typing when entering file names)
     s - toggle lpo function exerciser (to enable access to exported lpo functions)
     g - toggle gpx function exerciser (to enable access to exported gpx functions)
+    t - toggle analysis tools (to enable access to commands built on lpo and gpx)
 
 To select an option, enter the corresponding letter or number when prompted.
 
@@ -59,13 +60,17 @@ Read MPS file
 
 This option uses the ReadMpsFile function to populate the internal lpo data structures
 from an MPS file. Although this single function is included in the lpo function
-exerciser, it is important enough to be included in the main menu.
+exerciser, it is important enough to be included in the main menu. Since lpo does
+not accept the OBJSENSE section of free MPS files, the section is removed before
+the file is passed to ReadMpsFile, and the sense it gives is kept as the sense of
+the model. The same applies to option 42 and to every file read by the tools.
 
 
 Write MPS file
 
 Similarly, this option consists of the WriteMpsFile function which is also considered
-important enough to be included in the main menu.
+important enough to be included in the main menu. If the model is to be maximized,
+an OBJSENSE section is added after the NAME line of the file written.
 
 
 Solve problem
@@ -88,7 +93,34 @@ appear, it will be assumed that all of them are needed, and the name will be bas
 on the file name provided with the appropriate prefix added (see custom environment
 section for details).
 
-The next prompt allows the user to set the solver to be used, either Coin-OR or Cplex.
+The model is then read from the MPS file, if one was given, and checked as
+described for the validate tool. If errors are found, they are listed and the
+user may stop before solving. The user is then asked
+for the objective sense, MIN or MAX. The default is the sense of the model, which
+is MIN unless the MPS file it was read from has an OBJSENSE section (either
+"OBJSENSE MAX" on one line, or OBJSENSE with MAX on the next line). Since lpo
+always minimizes, a model to be maximized is solved as the minimization of the
+negated objective, and the objective value, reduced costs and duals are negated
+back before they are displayed. The sense applies to every solver, including
+worker processes used in a race. Note that the Cplex solution file written by
+lpo holds the values of the negated problem.
+
+The reduced MPS file holds the model left once the reductions are done, and can
+be handed to any external solver. It starts with comment lines giving the source
+of the model, the reductions applied and the maxiter used, the numbers of rows,
+columns and elements removed, the objective sense, and the time it was written.
+Unlike the Cplex solution file, it holds the original objective of a model to
+be maximized, together with an OBJSENSE section.
+
+The next prompt allows the user to set the solver to be used, either Coin-OR or Cplex,
+or to race them against each other. In a race, each solver runs in a separate
+worker process (a copy of this program) on its own copy of the model, applying the
+same reductions. The first solver to return a solution wins, the others are
+cancelled, and the time taken by each solver is reported. The winning solution
+becomes the lpo solution as usual. Output files are not written in a race.
+
+Once a solution is obtained, it is verified against the original model as
+described for the verify tool, and the result is displayed.
 
 The next prompt allows the user to specify which matrix-reduction operations to
 apply, and whether to solve the problem. The high-level options are "all" (apply all
@@ -97,11 +129,22 @@ or blank (carriage-return) to set each flag independently. The user must explici
 enter "Y" at each of the prompts to set the corresponding to "true", since any other
 response to the prompt will leave the flag in its default "false" state.
 
+The last prompt allows the user to set a wall-clock time limit, in seconds, for
+the solve. If left empty, the solve runs until the solver returns.
+
 After all prompts have been answered, the populated control structure is passed
 to CoinSolveProb or CplexSolveProb which returns a solution, or an error. If no
 error occurred, the user has the option to display the results. The results may
 also be displayed at a later time using the "Show lpo solution" option.
 
+A solve in progress can be aborted by pressing Ctrl-C, or is aborted when the
+time limit expires. Any solver process started by lpo is killed, and control
+returns to the main prompt with an "interrupted" or "time limit exceeded" error.
+The rest of the session is not affected, but the lpo data structures are left in
+whatever state the solver reached. Pressing Ctrl-C at any other time terminates
+the program cleanly, closing Cplex first if a problem was created by the gpx
+function exerciser.
+
 
 Reduce matrix
 
@@ -110,7 +153,13 @@ into the internal data structures, most likely by an earlier call to ReadMpsFile
 TransFromGpx if converting from gpx data structures, or some other similar
 mechanism. The user is prompted to specify which matrix-reduction operations are
 to be performed using the same set of questions requiring "Y" or "N" responses,
-the problem is reduced, and the system is left in this state. The user may then
+the user is asked for the objective sense, the problem is reduced (as a
+minimization of the negated objective if the sense is MAX, since some reductions
+depend on the sign of the objective), and the system is left in this state. The
+objective of the reduced model keeps its original sign. If a file name is given
+at the prompt (a base name with the reduced matrix prefix added if the custom
+environment is enabled), the reduced model is also written to an MPS file with
+the same comment lines as for the solve problem option. The user may then
 perform additional operations by independently calling other lpo or gpx functions
 as needed.
 
@@ -123,7 +172,8 @@ exercisers.
 
 Show lpo input
 
-This option shows the lpo input data structures in their raw form. It is not "pretty",
+This option shows the lpo input data structures in their raw form, preceded by
+the problem name, objective row index, and objective sense. It is not "pretty",
 but displays all fields of the various lists, and is useful when exercising other
 functions (e.g. DelRow or DelCol). To display a prettier version of the model,
 please use one of the other "Print" functions provided for this purpose.
@@ -164,6 +214,7 @@ which control the toggles and their default state are:
 
    var lpoMenuOn  bool = false   // Flag for enabling lpo functions   
    var gpxMenuOn  bool = false   // Flag for enabling gpx functions   
+   var toolMenuOn bool = false   // Flag for enabling analysis tools
    var custEnvOn  bool = false   // Flag for enabling custom paths and names
 
 
@@ -177,6 +228,12 @@ Toggle gpx function exerciser
 This toggle is used to enable or disable the options which are available to exercise
 individual gpx functions. By default, the gpx function exerciser is disabled.
 
+Toggle analysis tools
+
+This toggle is used to enable or disable the analysis tools, which are commands
+built on top of the lpo and gpx functions rather than wrappers for them. By
+default, the analysis tools are disabled.
+
 Toggle for custom environment
 
 This toggle controls how file names are handled by this program. If all files are
@@ -225,10 +282,10 @@ are listed below.
  34 - GetStatistics    - Get the model statistics.
  35 - GetTempDirPath   - Get the current path of the temp directory.
  36 - InitModel        - Initialize the lpo input data structures.
- 37 - PrintCol         - Prints the rows in which the column, specified by its index, occurs.
+ 37 - PrintCol         - Prints the rows in which the column, specified by its index or name, occurs.
  38 - PrintModel       - Prints the model in equation format.
  39 - PrintRhs         - Prints the RHS of all constraints.
- 40 - PrintRow         - Prints the row, specified by its index, in equation format.
+ 40 - PrintRow         - Prints the row, specified by its index or name, in equation format.
  41 - PrintStatistics  - Prints the model statistics.
  42 - ReadMpsFile      - Reads MPS file and populates internal data structures.
  43 - ReduceMatrix     - Performs the matrix-reduction operations specified.
@@ -241,6 +298,14 @@ are listed below.
  50 - WriteMpsFile     - Writes the model to an MPS file.
  51 - WritePsopFile    - Writes the pre-solve operations (PSOP) to a text file.
 
+Options 22, 23, 31, 32, 37 and 40 accept the name of a row or column wherever
+they ask for its index. A name made up of digits, which would be read as an
+index, must be preceded by '=', e.g. "=100".
+
+Option 29 asks for the objective sense, defaulting to the sense in the MPS file.
+Cplex reads the OBJSENSE section itself, so if a different sense is chosen, Cplex
+is given a copy of the file with the section replaced.
+
 GPX FUNCTION EXERCISER
 
 This section lists the options used to exercise individual gpx functions. The same
@@ -279,6 +344,608 @@ are listed below.
  82 - WriteProb       - Writes the problem loaded into Cplex to a file using the
                         format specified.
 
+ANALYSIS TOOLS
+
+This section lists the analysis tools. Unlike the other options, tools are
+selected by name, and any arguments may be entered on the same line as the name,
+separated by spaces. Arguments which are not entered on the command line are
+prompted for.
+
+ batch [pattern]      - Solves every model in a directory, or matching a glob.
+ bench [pattern]      - Times reading, reducing and solving models repeatedly.
+ verify [file]        - Checks the lpo solution against the original model.
+ pscheck [file]       - Compares solves with and without each reduction.
+ set param name value - Sets a solver parameter, or resets it with "default".
+ show params          - Lists the solver parameters and their values.
+ load params [file]   - Sets solver parameters from a file.
+ objrow [name]        - Lists the free rows and chooses the objective function.
+ ranging [file|-] [basis] - Reports sensitivity ranges of the lpo LP solution.
+ iis                  - Finds an irreducible infeasible subsystem of the model.
+ elastic [rows]       - Relaxes selected rows and minimizes their violation.
+ sweep [name start end step] - Re-solves over a range of a RHS or cost.
+ scenario [file] [names] - Compares what-if scenarios with the base model.
+ pool solve [size] [gap] - Collects distinct MIP solutions within a gap.
+ pool list|show|diff|export - Works with the solutions of the pool.
+ edit [command]       - Adds or changes rows, columns, coefficients and bounds.
+ delete rows|cols [selection] - Deletes the rows or columns selected.
+ keep rows|cols [selection]   - Deletes all but the rows or columns selected.
+ trace [view]         - Traces and browses the changes made by ReduceMatrix.
+ postsolve [soln] [psop] [format] - Recovers the full solution from a reduced one.
+ tighten [workers] [passes] [check] - Tightens column bounds concurrently.
+ scale method [pow2]  - Scales rows and columns, showing coefficient ranges.
+ health [text|json] [file] - Reports statistics and numerical health.
+ validate [file]      - Lists errors and warnings found in the model.
+ find var|rows|cols [selection] - Finds rows and columns by name or condition.
+
+Batch solve
+
+This tool solves every MPS file in a directory, or every file matching a glob
+pattern (e.g. "D:/Data/*.mps"), with the solver and reductions chosen once for
+all models. When a directory is given, the files with the ".mps" extension or the
+custom environment extension are used, except for files beginning with one of the
+custom environment prefixes. If the custom environment is enabled, the directory
+is added in front of the pattern.
+
+Since lpo keeps the model in package global variables, each model is solved in a
+separate worker process, which is a copy of this program. The number of workers
+running at the same time may be set; by default it is the number of CPUs. A model
+which fails to read or solve, or exceeds the optional time limit, is reported and
+does not stop the run. Ctrl-C stops the run, and models not yet started are
+reported as not run.
+
+A summary table is printed giving, for each model, the number of rows and columns
+before and after reduction, the objective value, the time taken and the status.
+The same information, including element counts, may be written to a CSV file.
+The reduced sizes and objective value of a model not solved to optimality are
+shown as "-" in the table and left empty in the CSV file.
+
+Benchmark
+
+This tool is used to check whether a new version of lpo, or of a solver, changed
+the time taken, or the objective value obtained, for a set of models. The models
+are selected as for the batch solve, and each is run the number of times
+requested, one run at a time in this process so that timings are not disturbed.
+
+Each run times four phases separately: reading the MPS file, TightenBounds on the
+model as read, ReduceMatrix with the reductions chosen (starting again from the
+model as read), and solving the reduced model. For each phase the median, 90th
+percentile, minimum and maximum over all runs are calculated. Note that the
+objective value is that of the reduced model.
+
+The results may be saved to a JSON file to serve as a baseline. When a baseline
+is given, the results are compared against it, and any phase whose median time
+grew by more than the allowed percentage (ignoring differences under 10 ms), and
+any objective value which differs by more than the relative tolerance, is
+reported. After the benchmark, the model, objective sense and solution held
+before it are restored.
+
+Verify solution
+
+This tool checks the lpo solution independently of the solver. The original,
+unreduced, model is read from the MPS file given, or taken from the lpo data
+structures if no file is given. The value of each variable in the solution is
+substituted into every row, in the same way as CalcLhs and CalcConViolation do,
+and checked against the row limits, the column bounds, and integrality. The
+objective value is also recomputed and compared to the one reported.
+
+The report gives the maximum absolute and relative violation, where relative
+violations are scaled by the magnitude of the limit violated, and lists the worst
+violations. The solution passes if no relative violation, nor the difference in
+objective value, exceeds the tolerance, and every column has a value.
+
+The same check is done automatically after every solve by the solve problem
+command, using the model read from the input file, or the data structures as
+they were before the solve. The tolerance set by this tool is also used for the
+automatic check.
+
+Presolve check
+
+Each matrix reduction performed by ReduceMatrix (DelRowNonbinding, DelRowSingleton,
+DelColSingleton and DelFixedVars) should leave the optimal objective value
+unchanged. This tool checks that it does, by solving the model, read from an MPS
+file or taken from the data structures, several times: with no reductions, with
+each reduction on its own, and with all of them. Every solve starts from the
+original model. A model read from a file is solved with the objective sense given
+in the file.
+
+Each solution is compared with the unreduced one, and verified against the
+original model as described for the verify tool. A run fails if its objective
+value differs by more than the tolerance, or its solution does not pass
+verification. If only the variable values differ, the run is flagged with a
+warning, since the model may have more than one optimal solution.
+
+The reductions whose runs failed are reported as the ones to blame, and the PSOP
+file written during each failed run is saved to the directory given, with the
+PSOP prefix and the name of the reductions. Once the check is over, the data
+structures hold the model they held before it, and the objective sense and lpo
+solution are as they were.
+
+Solver parameters
+
+Solver parameters are held in a single store, so that values set once are used
+by every solve: the solve problem command, batch, bench, pscheck, race mode, and
+the gpx exerciser. The parameters known are:
+
+ maxiter  - Number of reduction passes made by lpo (MaxIter), used in place of
+            the defaults of 10 for solves and 20 for ReduceMatrix.
+ opttol   - Cplex optimality tolerance (CPX_PARAM_EPOPT).
+ feastol  - Cplex feasibility tolerance (CPX_PARAM_EPRHS).
+ mipgap   - Cplex relative MIP gap (CPX_PARAM_EPGAP).
+ threads  - Cplex number of threads (CPX_PARAM_THREADS).
+ itlim    - Cplex simplex iteration limit (CPX_PARAM_ITLIM).
+ nodelim  - Cplex MIP node limit (CPX_PARAM_NODELIM).
+ timelim  - Cplex time limit in seconds (CPX_PARAM_TILIM).
+
+Any other Cplex parameter may be set by prefixing its name with "cplex.", e.g.
+"set param cplex.CPX_PARAM_SCAIND 1". The Cplex parameters are written to a
+parameter file in the lpo temp directory, which Cplex reads through the
+ILOG_CPLEX_PARAMETER_FILE environment variable whenever a Cplex environment is
+opened. Worker processes inherit the environment, so they use the same values.
+
+Since lpo provides no way of passing options to Coin-OR, only maxiter reaches
+the Coin-OR solve paths; "show params" lists the paths each parameter reaches.
+Cplex reads the parameters only when a problem is created in the gpx exerciser,
+so a warning is printed if they are changed afterwards and the problem is then
+optimized.
+
+A parameter file for "load params" holds one "name value" pair per line, and
+lines starting with '#' are ignored.
+
+Objective row
+
+An MPS file may hold several free (N) rows, e.g. alternative cost vectors, of
+which the first is taken as the objective function. This tool lists the free
+rows of the model in the data structures, with the number of non-zero
+coefficients in each, marking the current objective (lpo.ObjRow). The row named
+on the command line, or chosen at the prompt, becomes the objective.
+
+The chosen row is moved to the position of the first free row, exchanging places
+with the row there, so that the choice is kept by WriteMpsFile and TransToGpx,
+and by any program reading the MPS file written. The lpo solution and the parsed
+Cplex solution are cleared, since they belong to the previous objective.
+
+Sensitivity ranges
+
+This tool reports, for the current lpo solution of an LP, the range over which
+each objective coefficient may vary without the optimal basis changing, and the
+range over which each right-hand side may vary with the duals staying valid. The
+model is read from the MPS file given, or taken from the data structures, as for
+the verify tool, and the ranges refer to the objective sense of the last solve.
+
+Since neither lpo nor gpx gives access to the ranging of the Cplex libraries,
+the ranges are obtained from the Cplex interactive optimizer ("cplex") if it is
+found in the path: the model is written to a temporary MPS file, solved again,
+and the output of "display sensitivity obj" and "display sensitivity rhs" is
+read. Otherwise, or if "basis" is given on the command line, e.g. "ranging
+basis" for the model in the data structures or "ranging model.mps basis" for a
+file, the ranges are computed from the solution. A file name of "-" also stands
+for the data structures. Each row is given a logical variable equal to its
+left-hand side, the variables strictly between their bounds are made basic, and
+the basis is completed with the variables at a bound having the smallest reduced
+costs and duals. Ranges then follow from the factorized basis matrix, which is held dense,
+so this is limited to models with up to 3000 rows. If the solution is
+degenerate, another optimal basis may give different ranges, and a warning is
+printed when the basis identified shows signs of it.
+
+For each column, the report gives its status (BS basic, LL or UL at its lower or
+upper bound), value, cost, reduced cost, and the lowest and highest cost. For
+each row, it gives its status, activity, the right-hand side ranged (the bound
+at which the row is binding, or the finite one if it is not binding), dual, and
+the lowest and highest value of that right-hand side. The report may also be
+written to a CSV file.
+
+Irreducible infeasible subsystem
+
+When the model in the data structures is infeasible, this tool finds an IIS: a
+set of rows and column bounds which is infeasible, but becomes feasible if any
+one of them is removed. It is usually far smaller than the model, and points to
+the conflicting data.
+
+The search is a deletion filter over repeated feasibility solves with the solver
+selected, with the objective set to zero and no reductions. Every constraint row
+and every finite column bound is a candidate. Blocks of candidates are removed
+together, a row by giving it infinite limits and a bound by making it infinite,
+and a block is dropped for good if the model stays infeasible without it. The
+blocks start at half the candidates and are halved down to single candidates, so
+that large parts of the model are discarded with few solves, and the final pass
+guarantees that the set is irreducible. Since lpo does not report why a solve
+failed, a solve counts as infeasible only if its error message holds the
+status text of the solver for an infeasible model: "infeasible" (Cplex
+"Infeasible" and "Integer infeasible", Clp "Primal infeasible", Cbc "Problem is
+infeasible") or "no feasible solution" (Cbc). Any other failure, e.g. a solver
+not present or a numerical failure, stops the search and the message received
+is shown. Ctrl-C stops the search.
+
+The rows of the IIS are printed in the format of PrintRow, followed by its
+bounds. The IIS may be written to an MPS file, holding its rows and the columns
+appearing in them, with only the bounds belonging to the IIS, and an empty
+objective. Once the search is over, the data structures hold the original model.
+
+Elastic model
+
+As an alternative to the IIS, this tool shows how far an infeasible model is from
+being feasible. It builds an elastic copy of the model in the data structures,
+in which each selected row with a finite upper limit gets a nonnegative column
+(named with the EUP_ prefix) allowing its left-hand side to exceed the limit,
+and each selected row with a finite lower limit a column (ELO_ prefix) allowing
+it to fall below the limit. The rows are selected with "all" (the default), a
+row type (E, L or G), or a pattern matched against the row names, using * and ?
+as wildcards, e.g. "cap_*".
+
+Each violation column costs the penalty weight per unit in the objective. By
+default the original objective is dropped, so the elastic model minimizes the
+total violation; if it is kept, the model trades off the original objective,
+converted to a minimization, against the violations. The elastic model is solved
+with the solver selected, without reductions, and the rows which had to be
+relaxed are listed with the limit violated, the activity, and the amount of the
+violation, largest first, together with the total violation and the value of the
+original objective at the elastic solution.
+
+The data structures and the lpo solution are left unchanged, but the elastic
+model may be written to an MPS file.
+
+Parametric sweep
+
+This tool solves the model in the data structures at a series of values of one
+right-hand side or objective coefficient, from start to end in steps of step. If
+the name is that of a constraint row, its right-hand side is swept: the upper
+limit of an L row, the lower limit of a G row, or both limits of an E row, with
+both finite limits of a ranged row moved together. Otherwise the name must be a
+column, and its objective coefficient is swept. A sweep has at most 10000
+points.
+
+The objective value is tabulated at each point, together with the values of the
+variables and the duals of the rows chosen, entered as comma-separated lists.
+Points at which no solution is obtained are listed with the reason. The table
+may be written to a CSV file.
+
+With Cplex, the sweep is made on a single problem created through gpx, changing
+one coefficient between points so that each solve starts from the previous
+basis; no gpx exerciser problem may be open at the time. With Coin-OR, or if the
+gpx problem cannot be used, every point is written to its own MPS file and the
+points are solved in parallel, as for the batch solve. Ctrl-C stops the sweep.
+The data structures and the lpo solution are left unchanged.
+
+What-if scenarios
+
+This tool applies scenarios, each a named set of edits read from a scenario
+file, to copies of the model in the data structures, and compares the solution
+of each with that of the base model. All the scenarios in the file are applied
+unless some are named after the file name. A scenario file looks like this:
+
+  # Blank lines and lines starting with '#' are ignored.
+  scenario high_demand
+    rhs    DEMAND1  120         (set the right-hand side of a row)
+    bound  X3  up  50           (lo, up or fix a column at a value)
+    bound  X4  free             (make a column free)
+  scenario no_plant2
+    delrow CAP2                 (delete a row)
+    delcol Y2                   (delete a column)
+    coef   COST  X1  4.5        (set a coefficient, adding it if needed)
+
+The comments in parentheses are not part of the format. Edits before the first
+scenario line make up a scenario named after the file. Values may be "inf" or
+"-inf". The right-hand side is set as for the parametric sweep, and the
+objective row may not be deleted. Edits are applied in order, with rows and
+columns found by name, and every scenario is checked before anything is solved.
+
+The base model and each scenario are solved with the solver selected, without
+reductions. The comparison shows, side by side, the status of each solve, the
+objective value and its change from the base, and the values of the variables
+entered at the prompt, or by default of the variables changing most from the
+base solution, up to 10. A variable deleted by a scenario is shown as
+"deleted". The comparison may be written to a CSV file. The data structures and
+the lpo solution are left unchanged.
+
+MIP solution pool
+
+"pool solve" collects up to size (default 5) distinct solutions of the MIP in
+the data structures whose objective values lie within the relative gap (default
+0.05) of the best one. The model is first solved with the solver selected,
+without reductions. A row is then added keeping the objective within the gap of
+the best value, measured against its absolute value or 1 if that is smaller.
+After each solution, a no-good cut is added excluding the values of its binary
+columns, and the model is solved again, until size solutions are found or the
+solver finds no further one. The cuts are the same for both solvers, since gpx
+does not give access to the Cplex solution pool.
+
+Solutions are told apart by their binary columns only: integer columns of type
+B, or with bounds 0 and 1. A model with no binary columns is rejected, and a
+warning is printed if it has general integer columns, which are not used by the
+cuts. Ctrl-C stops the collection, keeping the solutions found so far.
+
+The solutions are kept in the pool, best first, alongside the lpo solution, which
+is set to the best one; the data structures are left unchanged. Reinitializing
+the lpo data structures, or changing the objective row, clears the pool.
+
+ pool list            - Lists the solutions with their objective values, gap
+                        to the best, and number of variables differing from it.
+ pool show [k]        - Prints solution k in the format of the lpo solution.
+ pool diff [a] [b]    - Lists the variables whose values differ in solutions a
+                        and b.
+ pool export [file]   - Writes the solutions to a CSV file, with one column per
+                        solution and one line per variable after the objective.
+
+Model editor
+
+The lpo exerciser can only delete rows and columns (options 31 and 32). This
+tool adds and changes rows, columns, coefficients and bounds of the model in the
+data structures, finding them by name. An edit may be entered after "edit" on
+the command line; otherwise edits are read one per line until an empty line.
+
+ name: equation       - Adds a row, or replaces the coefficients and limits of
+                        an existing one (also entered as "row name: equation").
+ col name [lo [up [C|I|B]]] - Adds a column, continuous with bounds 0 and
+                        infinity by default; a binary column has bounds 0 and 1.
+ coef row column value - Sets a coefficient, adding it if needed.
+ bound column lo|up|fix|free [value] - Sets the bounds of a column.
+ rhs row value        - Sets the right-hand side of a row, as for the
+                        parametric sweep.
+ rename row|col old new - Renames a row or column.
+
+Rows are written as equations, such as
+
+  c5: 3 x1 + 2 x2 <= 10
+  bal: x1 - x2 = x3 + 4
+  cap: 2 <= 0.5 y1 + y2 <= 8
+  cost2: 4 x1 + 3.5 x2
+
+with <=, >= or = as relations, giving an L, G or E row, two relations of the
+same direction giving a ranged row, and none giving a free (N) row. Columns may
+appear on both sides and coefficients may be written "3 x1", "3x1" or "3*x1".
+Names end at spaces or at any of the characters "+-*<>=". Numbers start with a
+digit or a point, and "inf" alone as a term stands for infinity, so names such
+as NaN or Inf are read as columns (inf with a coefficient). Columns which do not
+exist are added, continuous with bounds 0 and infinity. The objective row may be
+replaced by another free row, but cannot be made a constraint; a model without
+an objective takes the first free row added, so a model can be built from
+scratch.
+
+Each edit is applied to a copy of the model, and the result is checked with
+AdjustModel; if either fails, the model is left as it was. A successful edit
+clears the solutions held, which belong to the previous model.
+
+Bulk deletion
+
+Unlike DelRow and DelCol (options 32 and 31), which delete one row or column by
+index or name, these tools delete every row or column meeting a selection, or with
+"keep", every one not meeting it. The selection is a list of conditions, all of
+which must be met:
+
+ name pattern         - Name matches the pattern, with * and ? as wildcards.
+ regex expression     - Name matches the regular expression.
+ type t               - Row type N, E, L or G, or column type C, I or B.
+ empty                - Row or column has no elements; for a column, elements
+                        in the objective row are not counted.
+ singleton            - Row or column has one element, counted as for empty.
+ fixed                - Lower and upper limits or bounds are equal.
+ free                 - Lower and upper limits or bounds are infinite.
+ lo|up op value       - Lower or upper limit of a row, or bound of a column,
+                        compared with a value using <, <=, >, >=, = or !=.
+ rhs op value         - Right-hand side of a row, its upper limit for an L row
+                        and its lower limit otherwise, compared as above.
+
+For example, "delete rows name CAP_2017_*" or "keep cols type I up <= 1". The
+objective row is never deleted. The rows or columns to delete are listed, with
+the number of elements removed, and are deleted in one pass once confirmed,
+keeping the order of the others. The result is checked with AdjustModel, and
+the model is left as it was if the check fails; otherwise the solutions held
+are cleared.
+
+Presolve trace
+
+ReduceMatrix only reports the totals of rows, columns and elements deleted.
+This tool runs ReduceMatrix with the reductions chosen, as for the solve problem
+command, and the maxiter parameter (default 20), has it write the PSOP file of
+the operations it applied, and reads the file back as the postsolve tool does.
+Each operation is a step of the trace, in the order applied, and its changes
+are recorded with the reduction that made them:
+
+ delrow               - A row was deleted; its type, limits and number of
+                        elements are shown.
+ delcol               - A column was deleted; a fixed column is shown as
+                        substituted at its value.
+ rowlimits            - The type or limits of a row kept changed.
+ colbounds            - The bounds of a column kept were tightened.
+ op                   - An operation which deleted no row or column.
+
+Since the PSOP file does not give the changes to the rows and columns kept,
+these are found by comparing the model before and after ReduceMatrix. Which
+operation made them is not known, so they are listed after the operations with
+the rule "inferred", and marked as inferred in the JSON export. If the PSOP file
+cannot be read, or holds no operations, no trace is made and the error is
+reported. A note is printed if the operations do not account for all the rows
+and columns deleted.
+A summary of the changes by reduction and kind is printed, and the trace can
+then be browsed with the commands
+
+ list                 - Lists the changes passing the filters.
+ summary              - Prints the summary again.
+ rule|kind|step|row|col value - Keeps only the changes with that value,
+                        e.g. "row CAP3" to follow one row through the trace.
+ clear                - Removes all filters.
+ json file            - Writes the changes passing the filters to a JSON file.
+
+until an empty line is entered. "trace view" browses the last trace again. The
+data structures are left unchanged.
+
+Offline postsolve
+
+This tool recovers the solution of the original model from a solution of its
+reduced model obtained elsewhere, for example by solving with another solver an
+MPS file written after ReduceMatrix, and from the PSOP file written by option 51
+or by psCtrl.FileOutPsop when the model was reduced. The original model need
+not be held in the data structures. The solution file is read as
+
+ xml                  - A Cplex solution file (.xml or .sol).
+ coin                 - A solution written by the Coin-OR solvers with the
+                        "solu" command: a status line followed by the index,
+                        name, value and reduced cost of each column.
+ csv                  - One "name,value" pair per line (.csv).
+
+the format being taken from the file extension unless given on the command
+line. The PSOP file is entered at the prompt, or given on the command line as
+"-" for none. It is read as a list of operations in the order applied, each
+starting with a line holding its number, from 1, and its type, one of
+DEL_ROW_NONBINDING, DEL_ROW_SINGLETON, DEL_COL_SINGLETON or DEL_FIXED_VAR, and
+followed by the rows, columns and elements it removed:
+
+ ROW name type lo up  - A row removed, with its type and limits.
+ COL name type lo up  - A column removed, with its type and bounds.
+ ELEM row col value   - Elements removed, by row and column name; a line may
+                        hold several of them.
+
+Blank lines and lines starting with '*' are skipped. Any other line, an
+operation out of sequence or of another type, or a file with no operations is
+reported as an error, and nothing is recovered. This layout is the one the
+reader expects; it has not been checked against every version of lpo, so a
+PSOP file which lpo writes differently fails to read rather than giving a wrong
+solution.
+
+The operations are replayed in reverse order on the reduced solution. Fixed
+columns take their fixed value. A singleton column takes the value given by the
+row removed with it, with the other columns of the row at their values: the
+value solving the row if it is an equality, and otherwise the end of the range
+allowed by the row limits and the column bounds which is best for the objective
+coefficient of the column (its element in a row the operation does not delete)
+under the current objective sense, or the value nearest zero if it has no cost.
+A column whose range is empty, or whose best end is infinite, is listed as not
+given a value. The activity of each row deleted is then computed.
+
+If the original model is held in the data structures, any column still without
+a value is found, as a fallback, by solving the model with all other columns
+fixed, and the recovered solution is checked against the model. Otherwise, a
+column left without a value is an error. The recovered solution becomes the
+solution held by lpo, without duals, and can be written to a CSV file.
+
+Concurrent bound tightening
+
+TightenBounds (option 47) runs on a single thread, which can take minutes on
+models with millions of elements. This tool tightens the bounds of the columns
+with its own algorithm, in passes, sharing the rows of the model among a number
+of goroutines (by default the number of CPUs). On each pass, every row yields
+the bounds implied for its columns by its limits and by the smallest and
+largest activity of its other columns, all computed from the bounds at the
+start of the pass. The implied bounds are merged by taking the largest lower
+and smallest upper bound of each column, so the result is the same for any
+number of workers, including one. Implied bounds of integer columns are
+rounded, and implied bounds larger than 1e10 in magnitude are not used in place
+of infinite ones.
+
+This is not the algorithm of TightenBounds, which may update bounds as it goes
+through the rows, so its bounds may differ, usually by being tighter after the
+same number of passes. With "check" on the command line, TightenBounds is also
+run on the model, and the number of columns whose bounds agree, are tighter
+with lpo, or are looser with lpo is printed, with the first column differing.
+
+Passes are repeated until one tightens nothing, or the number of passes given
+on the command line, or the maxiter parameter (default 20), is reached. The
+number of lower and upper bounds tightened and the time taken are shown for
+each pass, and the bounds found replace those of the model. If a column ends up
+with a lower bound above its upper bound, the model is infeasible; the
+offending column is reported and the bounds are left unchanged.
+
+Scaling
+
+ScaleRows (option 44) scales the rows of the model in one way, and reports
+nothing about the result. This tool scales rows and columns by one of the
+methods
+
+ geom                 - One pass dividing each row, then each column, by the
+                        geometric mean of its smallest and largest coefficient.
+ equil                - One pass dividing each row, then each column, by its
+                        largest coefficient (max-norm equilibration).
+ iter                 - Geometric passes, repeated while each reduces the
+                        overall range by at least 10%, up to the maxiter
+                        parameter (default 20), then one equilibration pass.
+
+given on the command line or at the prompt. With "pow2", each factor is rounded
+to the nearest power of two, so that scaling adds no rounding error. The
+objective and other free rows are not scaled, nor are integer columns, whose
+values must stay integer.
+
+The range of the coefficients, the ratio of the largest to the smallest absolute
+non-zero value, is printed for the whole model, and for the rows and columns
+with the widest ranges after scaling together with their range before, so that
+it can be seen whether scaling helped. The ranges and factors of all rows and
+columns can be written to a CSV file. "scale report" prints the ranges of the
+model as it is.
+
+Once confirmed, the scaling is applied to the model held in the data structures
+and the solutions held are cleared. Solutions of the scaled model are in scaled
+units: the value of column j is x[j] divided by its factor. Scaling again
+combines the factors. "scale undo" restores the model as it was before scaling,
+and converts the lpo solution and the solution pool back to its units. If the
+model was changed after it was scaled, e.g. by edit or tighten, those changes
+would be lost, so a warning is printed and the undo goes ahead only if
+confirmed.
+
+Health report
+
+GetStatistics and PrintStatistics (options 34 and 41) give basic counts. This
+tool reports, for the model held in the data structures:
+
+ sizes                - Rows, columns and elements, and constraint rows by type.
+ non-zeros            - Histograms of the non-zeros of the constraint rows and
+                        of the columns outside the objective.
+ magnitudes           - Smallest and largest magnitude, and their ratio, of the
+                        coefficients, finite row limits, finite bounds and
+                        objective coefficients. Values which are not numbers
+                        (NaN) are counted apart and left out of the ranges.
+ columns              - Counts of free, fixed, boxed, lower and upper bounded,
+                        general integer and binary columns.
+ structure            - Counts of empty and singleton rows and columns, and of
+                        names used more than once.
+ warnings             - Rows and columns with values of magnitude 1e6 or more,
+                        possible big-M values or bounds standing for infinity,
+                        magnitude ranges above 1e9 over the model or within a
+                        row or column, NaN values, empty rows and columns, and
+                        duplicate names.
+
+The objective and other free rows are left out of the constraint counts. The
+report is printed as text by default, or as JSON with "health json", and is
+written to a file instead if a name is given after the format.
+
+Model validation
+
+This tool checks the model held in the data structures, or read from the MPS
+file given, and lists each finding with its severity, the row, column or
+element concerned, and a description. Elements are named by their index, e.g.
+"#12". Errors are listed first:
+
+ ERROR                - An element and the HasElems lists of its row and
+                        column disagree, or an index is out of range.
+ ERROR                - A coefficient is NaN or infinite, or a limit or bound
+                        is NaN.
+ ERROR                - A lower limit or bound is above the upper one.
+ ERROR                - A constraint row is empty, and its limits exclude zero.
+ ERROR                - A row or column name is used more than once, or two
+                        elements are in the same row and column.
+ WARNING              - An integer column has fractional bounds.
+ WARNING              - A name is longer than 8 characters, and will not fit
+                        in a fixed-format MPS file written by WriteMpsFile.
+
+The same check is made by the solve problem option once the model is loaded.
+If errors are found, they are listed and the user decides whether to solve the
+model anyway; warnings are only counted.
+
+Find
+
+This tool searches the model held in the data structures:
+
+ find var name        - Prints the rows in which the column occurs. The column
+                        can also be given by its index.
+ find rows selection  - Prints the rows meeting the selection.
+ find cols selection  - Lists the columns meeting the selection, with their
+                        type, bounds and number of elements.
+
+The selection is given as for bulk deletion, e.g. "find rows type G rhs > 100"
+or "find cols regex ^FLOW_.*_2026$"; a single word which is not a condition is
+taken as a name pattern, so that "find cols X*" lists the columns whose names
+start with X. Rows are printed in equation format as by PrintRow, preceded by
+their index, pausing every 50 items. Names are looked up through an index of
+the row and column names, which is rebuilt whenever it is found out of date.
+
 
 
 */
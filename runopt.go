@@ -13,6 +13,8 @@ import (
 	"fmt"
 	"github.com/go-opt/lpo"
 	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
 	"time"
 )
 
@@ -22,6 +24,7 @@ var devMenuOn  bool = false   // Flag for main development menu display
 var mainMenuOn bool = true    // Flag for main lpo function display
 var lpoMenuOn  bool = false   // Flag for enabling lpo functions   
 var gpxMenuOn  bool = false   // Flag for enabling gpx functions   
+var toolMenuOn bool = false   // Flag for enabling analysis tools
 var custEnvOn  bool = false   // Flag for enabling custom paths and names
 var pauseAfter int  = 50      // Number of items to print before pausing
 
@@ -42,6 +45,7 @@ var fExtension    string = ".txt"   // Extension of source data files in develop
 var lpCpSoln lpo.CplexSoln    // Cplex solution obtained from parsing xml file
 var lpStats  lpo.Statistics   // statistics data structure
 var psResult lpo.PsSoln       // solution received from lpo
+var psPool   []lpo.PsSoln     // solutions collected by the MIP solution pool, best first
 
 // Delimiter for sections in GPX input file
 
@@ -56,7 +60,7 @@ func printOptions() {
 
 	fmt.Println("\nAvailable Options (0 to EXIT):")
 	fmt.Println("")
-	fmt.Println(" s - lpo functions     g - gpx functions     c - custom env")
+	fmt.Println(" s - lpo functions     g - gpx functions     t - analysis tools    c - custom env")
 
   // The development menu would not be available to external users, who use the main menu.
   // For that reason, there is duplication of numbers referencing options in the two menus.
@@ -100,6 +104,33 @@ func printOptions() {
 	fmt.Println("81 - SolWrite         82 - WriteProb")
   }
 
+  if toolMenuOn {
+	fmt.Println("")
+	fmt.Println("batch - solve all models in a directory")
+	fmt.Println("bench - benchmark models against a baseline")
+	fmt.Println("verify - check the lpo solution against the original model")
+	fmt.Println("pscheck - compare solves with and without each reduction")
+	fmt.Println("set param - set a solver parameter   show params - list solver parameters")
+	fmt.Println("load params - set solver parameters from a file")
+	fmt.Println("objrow - list free rows and choose the objective function")
+	fmt.Println("ranging - sensitivity ranges of the lpo solution")
+	fmt.Println("iis - find an irreducible infeasible subsystem of the model")
+	fmt.Println("elastic - relax selected rows and minimize their violation")
+	fmt.Println("sweep - re-solve over a range of a right-hand side or cost")
+	fmt.Println("scenario - compare what-if scenarios from a file with the base model")
+	fmt.Println("pool solve|list|show|diff|export - MIP solution pool")
+	fmt.Println("edit - add or change rows, columns, coefficients and bounds by name")
+	fmt.Println("delete rows|cols - delete rows or columns matching a selection")
+	fmt.Println("keep rows|cols - keep only the rows or columns matching a selection")
+	fmt.Println("trace [view] - trace and browse the changes made by ReduceMatrix")
+	fmt.Println("postsolve [soln] [psop|-] [xml|coin|csv] - recover the full solution from a reduced one")
+	fmt.Println("tighten [workers] [passes] [check] - tighten column bounds with rows shared among workers")
+	fmt.Println("scale geom|equil|iter|report|undo [pow2] - scale rows and columns, show coefficient ranges")
+	fmt.Println("health [text|json] [file] - model statistics and numerical health report")
+	fmt.Println("validate [file] - check the model for errors before solving")
+	fmt.Println("find var|rows|cols [selection] - find rows and columns by name or condition")
+  }
+
 }
 
 //==============================================================================
@@ -131,7 +162,9 @@ func wpInitLpo() {
 	psResult.ObjVal  = 0.0
 	psResult.ConMap  = nil
 	psResult.VarMap  = nil
-
+	objSense         = senseMin
+	psPool           = nil
+	modelScale       = scaling{}
 
 	fmt.Printf("All lpo data structures have been initialized.\n")
 		
@@ -149,6 +182,7 @@ func wpInitLpo() {
 func wpSolveProb(solverFlag int) error {
 	var fileNameMPS         string  // MPS input file for the model
 	var filePsopOut         string  // output file for pre-solve reductions
+	var fileRdcdOut         string  // output file for the reduced matrix
 	var fileSolnOut         string  // output file for xml solution
 	var flagChoice          string  // flag selection read from user
 	var userString          string  // holder for general input from user
@@ -156,7 +190,10 @@ func wpSolveProb(solverFlag int) error {
 	var runColS, runFixedVars bool  // flags for column reductions
 	var runSolver             bool  // flag controlling if problem is solved
 	var useCoinSolver         bool  // flag indicating which solver to use
+	var raceSolvers           bool  // flag to race all solvers against each other
 	var psCtrl          lpo.PsCtrl  // control structure for reductions
+	var timeLimit    time.Duration  // wall-clock limit on the solve, 0 if none
+	var origModel         lpModel  // model before the solve, used for verification
 	var err                  error  // error received from called functions
 
 	
@@ -164,6 +201,7 @@ func wpSolveProb(solverFlag int) error {
 	fileNameMPS  = ""
 	fileSolnOut  = ""
 	filePsopOut  = ""
+	fileRdcdOut  = ""
 
 	// Enter input and output file names.	
 	fmt.Printf("Enter MPS input file name or <CR> to use data structures: ")
@@ -183,26 +221,47 @@ func wpSolveProb(solverFlag int) error {
 			// Create base name using input MPS file and tack on the right prefix.
 			fileSolnOut  = fPrefSolnOut + fileNameMPS
 			filePsopOut  = fPrefPsopOut + fileNameMPS
+			fileRdcdOut  = fPrefRdcMps  + fileNameMPS
 			// Add the full directory path and extension.
 			fileNameMPS  = dSrcDev + fileNameMPS  + fExtension			
 			fileSolnOut  = dSrcDev + fileSolnOut  + fExtension
 			filePsopOut  = dSrcDev + filePsopOut  + fExtension
+			fileRdcdOut  = dSrcDev + fileRdcdOut  + fExtension
 		}
 	} else {
 		fmt.Printf("Enter Cplex output file name or <CR> for none: ")
 		fmt.Scanln(&fileSolnOut)		
 		fmt.Printf("Enter PSOP output file name or <CR> for none: ")
 		fmt.Scanln(&filePsopOut)		
+		fmt.Printf("Enter reduced MPS output file name or <CR> for none: ")
+		fmt.Scanln(&fileRdcdOut)		
+	}
+
+	// The model is read here, rather than by the solver, so that the objective
+	// sense given in the file is known before the user is asked for it.
+	if fileNameMPS != "" {
+		if err = readMpsSense(fileNameMPS); err != nil {
+			return errors.Wrap(err, "wpSolveProb failed")
+		}
+	}
+
+	// Check the model before the remaining prompts, so that a model which
+	// cannot be solved is caught early.
+	if err = wpPreSolveCheck(); err != nil {
+		return errors.Wrap(err, "wpSolveProb failed")
 	}
+	objSense = wpGetObjSense(objSense)
 
 	// Decide which solver should be used.
 	switch solverFlag {
 		
 		case 0:
-			fmt.Printf("Do you wish to use Coin-OR instead of Cplex [Y|N]: ")
+			fmt.Printf("Do you wish to use Coin-OR instead of Cplex [Y|N], or R to race both: ")
 			fmt.Scanln(&flagChoice)
 			if flagChoice == "y" || flagChoice == "Y" {
 				useCoinSolver = true
+			} else if flagChoice == "r" || flagChoice == "R" {
+				raceSolvers = true
 			} else {
 				useCoinSolver = false
 			}
@@ -278,38 +337,54 @@ func wpSolveProb(solverFlag int) error {
 	psCtrl.DelColSingleton   = runColS
 	psCtrl.DelFixedVars      = runFixedVars
 	psCtrl.RunSolver         = runSolver
-	psCtrl.MaxIter           = 10
-	psCtrl.FileInMps         = fileNameMPS
+	psCtrl.MaxIter           = paramMaxIter(10)
+	psCtrl.FileInMps         = ""
 	psCtrl.FileOutSoln       = fileSolnOut
 	psCtrl.FileOutPsop       = filePsopOut
+	psCtrl.FileOutMpsRdcd    = fileRdcdOut
+
+	// All racing solvers would write to the same output files.
+	if raceSolvers && (psCtrl.FileOutSoln != "" || psCtrl.FileOutPsop != "" ||
+		psCtrl.FileOutMpsRdcd != "") {
+		fmt.Printf("Output files are not written when solvers are raced.\n")
+		psCtrl.FileOutSoln    = ""
+		psCtrl.FileOutPsop    = ""
+		psCtrl.FileOutMpsRdcd = ""
+	}
+
+	timeLimit = wpGetTimeLimit()
 
 	// Use Cplex or Coin-OR to solve the problem, and time how long it takes.
-	// If gpx is not present, the function call for Cplex must be commented out
-	// and if this function is called under those conditions, it will return an
-	// error indicating solver is not present.
+	// The solve can be aborted with Ctrl-C or by the time limit, in which case
+	// control returns to the main prompt with the model state left by lpo.
 	
+	// Keep the original model for verifying the solution, unless it will be read
+	// again from the input file.
+	if fileNameMPS == "" {
+		origModel = saveModel()
+	}
+
+	fmt.Printf("Solving, press Ctrl-C to abort the solve.\n")
 	startTime := time.Now()	
-	err = errors.New("Requested solver not present")
-	if useCoinSolver {
-		err = lpo.CoinSolveProb(psCtrl, &psResult)						
+	if raceSolvers {
+		err = wpRaceSolvers(timeLimit, psCtrl, &psResult)
 	} else {
-		// GPX_EXCLUDED: Comment out the following line if gpx is not installed.
-	  	err = lpo.CplexSolveProb(psCtrl, &psResult)			
-	}	
+		err = solveWithCancel(timeLimit, useCoinSolver, psCtrl, &psResult)
+	}
 	endTime := time.Now()
 			
 	if err != nil {
 		return errors.Wrap(err, "wpSolveProb failed")
 	} else {
-		fmt.Printf("\nOBJECTIVE FUNCTION = %f\n\n", psResult.ObjVal)
+		fmt.Printf("\nOBJECTIVE FUNCTION (%s) = %f\n\n", objSense, psResult.ObjVal)
 		fmt.Printf("Presolve removed %d rows, %d cols, and %d elements.\n",
 			psResult.RowsDel, psResult.ColsDel, psResult.ElemDel)
 		fmt.Printf("Solution has %d constraints and %d variables.\n", 
 			len(psResult.ConMap), len(psResult.VarMap))
 
 		// Display which files were used.			
-		if psCtrl.FileInMps != "" {
-			fmt.Printf("Input MPS file read:    '%s'\n", psCtrl.FileInMps)
+		if fileNameMPS != "" {
+			fmt.Printf("Input MPS file read:    '%s'\n", fileNameMPS)
 		} else {
 			fmt.Printf("Model read from internal data structures.\n")
 		}
@@ -319,6 +394,14 @@ func wpSolveProb(solverFlag int) error {
 		}
 
 		if psCtrl.FileOutMpsRdcd != "" {
+			source := fileNameMPS
+			if source == "" {
+				source = "model '" + lpo.Name + "' from internal data structures"
+			}
+			header := rdcdHeader(source, psCtrl, psResult.RowsDel, psResult.ColsDel, psResult.ElemDel)
+			if err = stampReducedMps(psCtrl.FileOutMpsRdcd, header, objSense == senseMax); err != nil {
+				fmt.Printf("WARNING: %s\n", err)
+			}
 			fmt.Printf("Reduced MPS file saved: '%s'\n", psCtrl.FileOutMpsRdcd)
 		}
 
@@ -327,7 +410,12 @@ func wpSolveProb(solverFlag int) error {
 		}
 		
 		fmt.Printf("\nStarted at:  %s\n",   startTime.Format("2006-01-02 15:04:05"))
-		fmt.Printf("Finished at: %s\n\n", endTime.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Finished at: %s\n", endTime.Format("2006-01-02 15:04:05"))
+
+		if psCtrl.RunSolver {
+			wpAutoVerify(fileNameMPS, origModel)
+		}
+		fmt.Printf("\n")
 
 		fmt.Printf("Do you want to see the detailed solution [Y|N]: ")
 		fmt.Scanln(&userString)
@@ -341,6 +429,58 @@ func wpSolveProb(solverFlag int) error {
 }
 
 
+//==============================================================================
+
+// callSolver passes the control structure to CoinSolveProb or CplexSolveProb,
+// depending on the useCoin flag, and returns the solution in psSoln. An input
+// MPS file is read here so that its OBJSENSE section is honoured, and a model
+// to be maximized is solved as the minimization of the negated objective.
+// If gpx is not present, the function call for Cplex must be commented out
+// and if this function is called under those conditions, it will return an
+// error indicating solver is not present.
+// In case of failure, function returns an error.
+func callSolver(useCoin bool, psCtrl lpo.PsCtrl, psSoln *lpo.PsSoln) error {
+	var err error  // error received from called functions
+
+	if psCtrl.FileInMps != "" {
+		if err = readMpsSense(psCtrl.FileInMps); err != nil {
+			return err
+		}
+		psCtrl.FileInMps = ""
+	}
+
+	if objSense == senseMax {
+		negateObjective()
+		defer negateObjective()
+	}
+
+	err = errors.New("Requested solver not present")
+	if useCoin {
+		err = lpo.CoinSolveProb(psCtrl, psSoln)						
+	} else {
+		// GPX_EXCLUDED: Comment out the following line if gpx is not installed.
+	  	err = lpo.CplexSolveProb(psCtrl, psSoln)			
+	}	
+
+	if err == nil && objSense == senseMax {
+		negateSoln(psSoln)
+	}
+
+	return err
+}
+
+//==============================================================================
+
+// wpShutdown releases any resources still held by the solvers before the
+// program terminates. The function accepts no arguments and returns no values.
+func wpShutdown() {
+
+	// GPX_EXCLUDED: Comment out the following line if gpx is not installed.
+	wpCloseCplex()
+
+	fmt.Println("\n===> NORMAL PROGRAM TERMINATION <===\n")
+}
+
 //==============================================================================
 
 // wpSolveCplex is a wrapper obtaining a solution directly from an MPS data file.
@@ -357,6 +497,8 @@ func wpSolveCplex() error {
 	var fileName       string  // MPS input file
 	var filePresolve   string  // presolve file used by Cplex
 	var fileSolnOut    string  // output solution file generated by Cplex
+	var fileCplexIn    string  // MPS file passed to Cplex
+	var sense          string  // objective sense chosen
 	var err             error  // error received from called functions
 
 	// Get the name of the source MPS file and generate other file names from the
@@ -381,12 +523,33 @@ func wpSolveCplex() error {
 		fmt.Scanln(&filePresolve)
 	}
 
+	// Cplex reads the OBJSENSE section itself, so the file is only copied, with
+	// the section replaced, if the user chose a different sense.
+	fileSense, skip, err := scanObjSense(fileName)
+	if err != nil {
+		return errors.Wrap(err, "wpSolveCplex failed")
+	}
+	sense       = wpGetObjSense(fileSense)
+	fileCplexIn = fileName
+	if sense != fileSense {
+		tmp, err := ioutil.TempFile("", "runopt_sense")
+		if err != nil {
+			return errors.Wrap(err, "wpSolveCplex failed")
+		}
+		tmp.Close()
+		defer os.Remove(tmp.Name())
+		if err = copyMpsSense(fileName, tmp.Name(), skip, sense); err != nil {
+			return errors.Wrap(err, "wpSolveCplex failed")
+		}
+		fileCplexIn = tmp.Name()
+	}
+
 	// Call the functions to solve the problem, parse the solution, and display
 	// the results.
 
 	fmt.Println("")	
 	
-	err = lpo.CplexSolveMps(fileName, fileSolnOut, filePresolve, &lpCpSoln)
+	err = lpo.CplexSolveMps(fileCplexIn, fileSolnOut, filePresolve, &lpCpSoln)
 	if err != nil {
 		return errors.Wrap(err, "wpSolveCplex failed solving problem")			
 	}
@@ -398,6 +561,7 @@ func wpSolveCplex() error {
 	fmt.Printf("\nMPS file read:      %s\n", fileName)
 	fmt.Printf("Cplex output:       %s\n", fileSolnOut)
 	fmt.Printf("Presolve file:      %s\n", filePresolve)
+	fmt.Printf("Objective sense:    %s\n", sense)
 	fmt.Printf("Objective value:    %f\n\n", lpCpSoln.Header.ObjValue)						
 
 	userString = ""
@@ -505,6 +669,7 @@ func wpReduceMtrx() error {
 	var runRowS      bool   // remove row singletons
 	var runColS      bool   // remove column singletons
 	var runFixedVars bool   // remove fixed variables
+	var fileRdcdOut string  // output file for the reduced matrix
 	var err         error   // error returned from called functions
 
 	// Initialize the variables, which also become the "none" option provided
@@ -557,19 +722,42 @@ func wpReduceMtrx() error {
 				
 	} // end else setting reduction flags
 
+	objSense = wpGetObjSense(objSense)
+
+	fmt.Printf("Enter reduced MPS output file name or <CR> for none: ")
+	fmt.Scanln(&fileRdcdOut)
+	if custEnvOn && fileRdcdOut != "" {
+		fileRdcdOut = dSrcDev + fPrefRdcMps + fileRdcdOut + fExtension
+	}
+
 	// Populate the control data structure and call ReduceMatrix.	
 	psCtrl.DelRowNonbinding = runTB
 	psCtrl.DelRowSingleton  = runRowS
 	psCtrl.DelColSingleton  = runColS
 	psCtrl.DelFixedVars     = runFixedVars
 	psCtrl.RunSolver        = false
-	psCtrl.MaxIter          = 20
+	psCtrl.MaxIter          = paramMaxIter(20)
 	psCtrl.FileInMps        = ""
 	psCtrl.FileOutSoln      = ""
 
-	if err = lpo.ReduceMatrix(psCtrl); err != nil {
+	rowsBefore, colsBefore, elemsBefore := len(lpo.Rows), len(lpo.Cols), len(lpo.Elems)
+	if err = reduceMatrixSense(psCtrl); err != nil {
 		return errors.Wrap(err, "wpReduceMtrx failed")
 	}
+
+	// The reduced model is written with its original objective, so it can be
+	// handed to any solver.
+	if fileRdcdOut != "" {
+		header := rdcdHeader("model '" + lpo.Name + "' from internal data structures", psCtrl,
+			rowsBefore - len(lpo.Rows), colsBefore - len(lpo.Cols), elemsBefore - len(lpo.Elems))
+		if err = writeMpsSense(fileRdcdOut); err != nil {
+			return errors.Wrap(err, "wpReduceMtrx failed to write reduced MPS file")
+		}
+		if err = stampReducedMps(fileRdcdOut, header, false); err != nil {
+			return errors.Wrap(err, "wpReduceMtrx failed")
+		}
+		fmt.Printf("Reduced MPS file saved: '%s'\n", fileRdcdOut)
+	}
 	
 	return nil
 }
@@ -584,7 +772,7 @@ func wpPrintLpoIn() {
 	var counter    int     // counter keeping track of number of lines printed
 
 	if lpo.Name != "" {
-		fmt.Printf("Problem [%s], obj. index %d\n", lpo.Name, lpo.ObjRow)
+		fmt.Printf("Problem [%s], obj. index %d, sense %s\n", lpo.Name, lpo.ObjRow, objSense)
 	} else {
 		fmt.Printf("WARNING: Problem name is empty.\n")
 	}
@@ -677,13 +865,22 @@ func wpPrintLpoIn() {
 // periodically so output does not scroll off the screen. The function accepts no
 // input and returns no values.
 func wpPrintLpoSoln() {
+
+	printPsSoln(psResult)
+}
+
+//==============================================================================
+
+// printPsSoln prints the solution provided in the same format as wpPrintLpoSoln,
+// which uses it for the lpo solution. The function returns no values.
+func printPsSoln(soln lpo.PsSoln) {
 	var userString string
 	var counter int
 	var index   int
 
 	// Check if the lists exist, and if they do, print them.
 					
-	if len(psResult.VarMap)	<= 0 {
+	if len(soln.VarMap)	<= 0 {
 		fmt.Printf("WARNING: Solution list of variables is empty.\n")
 	} else {
 		userString = ""
@@ -696,7 +893,7 @@ func wpPrintLpoSoln() {
 			
 			counter = 0
 			index   = 0
-			for psVarbName, psVarb := range psResult.VarMap {
+			for psVarbName, psVarb := range soln.VarMap {
 				fmt.Printf("%6d  %-10s     %15e %15e %15e\n", index, psVarbName,
 					psVarb.Value, psVarb.ReducedCost, psVarb.ScaleFactor)
 					
@@ -715,7 +912,7 @@ func wpPrintLpoSoln() {
 		} // end if printing varb list
 	} // end else varb list not empty	
 
-	if len(psResult.ConMap) <= 0 {
+	if len(soln.ConMap) <= 0 {
 		fmt.Printf("WARNING: Solution list of constraints is empty.\n")		
 	} else {
 		userString = ""
@@ -728,7 +925,7 @@ func wpPrintLpoSoln() {
 				
 			counter = 0
 			index   = 0
-			for psConName,psCon := range psResult.ConMap {
+			for psConName,psCon := range soln.ConMap {
 				fmt.Printf("%6d  %-10s %3s %15e %15e %15e %15e %15e\n",
 					index, psConName, psCon.Type,
 					psCon.Rhs, psCon.Slack, psCon.Pi, psCon.Dual, psCon.ScaleFactor)
@@ -774,7 +971,7 @@ func runWrapper() {
 		
 		cmdOption    = ""		
 		fmt.Printf("\nEnter a new option: ")
-		fmt.Scanln(&cmdOption)
+		cmdOption = readCommand()
 
 		switch cmdOption {
 
@@ -823,6 +1020,16 @@ func runWrapper() {
 				printOptions()				
 			}
 						
+		case "t":
+			if toolMenuOn {
+				toolMenuOn = false
+				fmt.Println("\nAnalysis tools menu commands will be disabled.")
+			} else {
+				toolMenuOn = true
+				fmt.Println("\nAnalysis tools menu commands will be enabled.")
+				printOptions()				
+			}
+						
 		case "c":
 			if custEnvOn {
 				fmt.Printf("\nCustomized environment disabled.\n")
@@ -841,7 +1048,7 @@ func runWrapper() {
 			}
 
 		case "0":
-			fmt.Println("\n===> NORMAL PROGRAM TERMINATION <===\n")
+			wpShutdown()
 			return
 
 
@@ -878,6 +1085,13 @@ func runWrapper() {
 				}
 			}
 
+			if toolMenuOn {
+				if err = runToolWrapper(cmdOption); err == nil {
+					// Found the command in analysis tools menu, continue
+					continue
+				}
+			}
+
 			if gpxMenuOn {
 				err = errors.New("Command not available")				
 				// GPX_EXCLUDED: Comment out the following line if gpx is not installed.
@@ -899,9 +1113,15 @@ func runWrapper() {
 
 //==============================================================================
 
-// main function calls the main wrapper. It accepts no arguments and returns
-// no values.
+// main function installs the interrupt handler and calls the main wrapper. It
+// accepts no arguments and returns no values.
 func main() {
 	
+	// A worker process only executes the job it was given.
+	if isWorker() {
+		os.Exit(runWorker())
+	}
+
+	initSignals()
 	runWrapper()
 }
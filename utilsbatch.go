@@ -0,0 +1,314 @@
+// This file contains the batch solve of all models in a directory, using a pool
+// of worker processes.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// batchEntry holds the outcome of solving one model in a batch.
+type batchEntry struct {
+	File    string         // MPS file solved
+	Status  string         // "optimal", or the reason no solution was obtained
+	Elapsed time.Duration  // wall-clock time including reading the model
+	Result  workerResult   // result reported by the worker
+}
+
+//==============================================================================
+
+// batchFiles returns the sorted list of files selected by the pattern. If the
+// pattern is a directory, the MPS files in it are selected, i.e. those with an
+// extension of ".mps" or the custom environment extension, skipping the files
+// this program writes using the custom environment prefixes. Otherwise the
+// pattern is treated as a glob. In case of failure, function returns an error.
+func batchFiles(pattern string) ([]string, error) {
+	var files []string   // files selected
+
+	info, err := os.Stat(pattern)
+	if err != nil || !info.IsDir() {
+		if files, err = filepath.Glob(pattern); err != nil {
+			return nil, errors.Wrapf(err, "Invalid pattern '%s'", pattern)
+		}
+		return files, nil
+	}
+
+	entries, err := ioutil.ReadDir(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot read directory '%s'", pattern)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		ext  := strings.ToLower(filepath.Ext(name))
+		if entry.IsDir() || (ext != ".mps" && ext != strings.ToLower(fExtension)) {
+			continue
+		}
+		if strings.HasPrefix(name, fPrefSolnOut) || strings.HasPrefix(name, fPrefPsopOut) ||
+			strings.HasPrefix(name, fPrefRdcMps) {
+			continue
+		}
+		files = append(files, filepath.Join(pattern, name))
+	}
+
+	return files, nil
+}
+
+//==============================================================================
+
+// runBatch solves every file with the solver and reductions specified, running
+// up to numWorkers worker processes at the same time. Each model is given at
+// most timeLimit to solve, if greater than zero. An interrupt stops the batch,
+// leaving the models not yet started marked as such. Entries are returned in
+// the same order as the files, and progress is printed as models complete.
+func runBatch(files []string, useCoin bool, psCtrl lpo.PsCtrl, numWorkers int,
+	timeLimit time.Duration) []batchEntry {
+	var entries []batchEntry  // outcome for each file
+	var printMu  sync.Mutex   // serializes progress output
+	var finished int          // number of models finished
+
+	entries = make([]batchEntry, len(files))
+	for i := range files {
+		entries[i].File   = files[i]
+		entries[i].Status = "not run"
+	}
+
+	workDir, err := ioutil.TempDir("", "runopt_batch")
+	if err != nil {
+		for i := range entries {
+			entries[i].Status = "failed: " + err.Error()
+		}
+		return entries
+	}
+	defer os.RemoveAll(workDir)
+
+	ctx, endSolve := beginSolve(0)
+	defer endSolve()
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entries[i] = solveBatchEntry(ctx, entries[i], useCoin, psCtrl, workDir,
+					strconv.Itoa(i), timeLimit)
+
+				printMu.Lock()
+				finished++
+				fmt.Printf("[%d/%d] %s: %s (%s)\n", finished, len(files), files[i],
+					firstLine(entries[i].Status), entries[i].Elapsed.Round(time.Millisecond))
+				printMu.Unlock()
+			}
+		}()
+	}
+
+	for i := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return entries
+}
+
+//==============================================================================
+
+// solveBatchEntry solves one model of a batch in a worker process and returns
+// the entry updated with the outcome.
+func solveBatchEntry(ctx context.Context, entry batchEntry, useCoin bool,
+	psCtrl lpo.PsCtrl, workDir string, tag string, timeLimit time.Duration) batchEntry {
+
+	if ctx.Err() != nil {
+		return entry
+	}
+
+	if timeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeLimit)
+		defer cancel()
+	}
+
+	psCtrl.FileInMps = entry.File
+	job := workerJob{UseCoin: useCoin, PsCtrl: psCtrl}
+
+	startTime := time.Now()
+	cmd, resultFile, err := startWorker(job, workDir, tag)
+	if err == nil {
+		entry.Result, err = waitWorker(ctx, cmd, resultFile)
+	}
+	entry.Elapsed = time.Since(startTime)
+
+	switch {
+	case err == errTimeLimit:
+		entry.Status = "time limit"
+	case err == errInterrupted:
+		entry.Status = "interrupted"
+	case err != nil:
+		entry.Status = "failed: " + err.Error()
+	case entry.Result.Err != "":
+		entry.Status = "failed: " + entry.Result.Err
+	default:
+		entry.Status = "optimal"
+	}
+
+	return entry
+}
+
+//==============================================================================
+
+// writeBatchCsv writes the summary of a batch to a CSV file, one line per model,
+// giving its size before and after reduction, the objective value, the status
+// and the time taken. In case of failure, function returns an error.
+func writeBatchCsv(fileName string, entries []batchEntry) error {
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create file %s", fileName)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"file", "rows", "cols", "elems", "rows_reduced", "cols_reduced",
+		"elems_reduced", "objective", "status", "seconds"})
+
+	for _, entry := range entries {
+		var record []string
+		res := entry.Result
+
+		record = append(record, entry.File)
+		if res.Rows == 0 && res.Cols == 0 {
+			// Model was never read, so no sizes are known.
+			record = append(record, "", "", "", "", "", "")
+		} else if entry.Status != "optimal" {
+			// Reduced sizes are only known once the solve succeeds.
+			record = append(record,
+				strconv.Itoa(res.Rows), strconv.Itoa(res.Cols), strconv.Itoa(res.Elems), "", "", "")
+		} else {
+			record = append(record,
+				strconv.Itoa(res.Rows), strconv.Itoa(res.Cols), strconv.Itoa(res.Elems),
+				strconv.Itoa(res.Rows  - res.PsSoln.RowsDel),
+				strconv.Itoa(res.Cols  - res.PsSoln.ColsDel),
+				strconv.Itoa(res.Elems - res.PsSoln.ElemDel))
+		}
+
+		if entry.Status == "optimal" {
+			record = append(record, strconv.FormatFloat(res.PsSoln.ObjVal, 'g', -1, 64))
+		} else {
+			record = append(record, "")
+		}
+
+		record = append(record, firstLine(entry.Status),
+			strconv.FormatFloat(entry.Elapsed.Seconds(), 'f', 3, 64))
+		w.Write(record)
+	}
+
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return errors.Wrapf(err, "Failed to write file %s", fileName)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// wpBatchSolve solves every model in a directory, or matching a glob, with the
+// solver and reductions chosen once for all models. The models are solved by a
+// pool of worker processes, so that a model which fails, or crashes the solver,
+// does not affect the others. A summary is printed and written to a CSV file.
+// In case of failure, function returns an error.
+func wpBatchSolve() error {
+	var pattern    string         // directory or glob of files to solve
+	var fileCsv    string         // summary output file
+	var userString string         // input provided by user
+	var numWorkers int            // number of worker processes
+	var psCtrl     lpo.PsCtrl     // control structure passed to each worker
+	var timeLimit  time.Duration  // limit on each solve, 0 if none
+
+	pattern = promptArg(0, "Enter directory or glob of MPS files: ")
+	if custEnvOn {
+		pattern = dSrcDev + pattern
+	}
+
+	files, err := batchFiles(pattern)
+	if err != nil {
+		return errors.Wrap(err, "wpBatchSolve failed")
+	}
+	if len(files) == 0 {
+		return errors.Errorf("wpBatchSolve found no MPS files in '%s'", pattern)
+	}
+	fmt.Printf("%d models selected.\n", len(files))
+
+	useCoin := wpGetUseCoin()
+	wpGetPsFlags(&psCtrl)
+	psCtrl.RunSolver = true
+	psCtrl.MaxIter   = paramMaxIter(10)
+
+	numWorkers = runtime.NumCPU()
+	fmt.Printf("Enter number of parallel workers or <CR> for %d: ", numWorkers)
+	fmt.Scanln(&userString)
+	if userString != "" {
+		if numWorkers, err = strconv.Atoi(userString); err != nil || numWorkers < 1 {
+			return errors.Errorf("'%s' is not a valid number of workers.", userString)
+		}
+	}
+
+	timeLimit = wpGetTimeLimit()
+
+	fmt.Printf("Enter CSV summary file name or <CR> for none: ")
+	fmt.Scanln(&fileCsv)
+	if custEnvOn && fileCsv != "" {
+		fileCsv = dSrcDev + fileCsv + ".csv"
+	}
+
+	fmt.Printf("\nSolving %d models with %d workers, press Ctrl-C to stop.\n", len(files), numWorkers)
+	entries := runBatch(files, useCoin, psCtrl, numWorkers, timeLimit)
+
+	fmt.Printf("\n%-30s %8s %8s %8s %8s %15s %10s  %s\n", "FILE", "ROWS", "COLS",
+		"RDC ROWS", "RDC COLS", "OBJECTIVE", "SECONDS", "STATUS")
+	for _, entry := range entries {
+		res := entry.Result
+
+		// Sizes of a model never read, and results of a failed solve, are shown
+		// as "-", as they are left empty in the CSV summary.
+		rows, cols, rdcRows, rdcCols, objVal := "-", "-", "-", "-", "-"
+		if res.Rows != 0 || res.Cols != 0 {
+			rows, cols = strconv.Itoa(res.Rows), strconv.Itoa(res.Cols)
+		}
+		if entry.Status == "optimal" {
+			rdcRows = strconv.Itoa(res.Rows - res.PsSoln.RowsDel)
+			rdcCols = strconv.Itoa(res.Cols - res.PsSoln.ColsDel)
+			objVal  = fmt.Sprintf("%e", res.PsSoln.ObjVal)
+		}
+		fmt.Printf("%-30s %8s %8s %8s %8s %15s %10.3f  %s\n", filepath.Base(entry.File),
+			rows, cols, rdcRows, rdcCols, objVal, entry.Elapsed.Seconds(), firstLine(entry.Status))
+	}
+
+	if fileCsv != "" {
+		if err = writeBatchCsv(fileCsv, entries); err != nil {
+			return errors.Wrap(err, "wpBatchSolve failed")
+		}
+		fmt.Printf("\nSummary written to '%s'.\n", fileCsv)
+	}
+
+	return nil
+}
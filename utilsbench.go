@@ -0,0 +1,395 @@
+// This file contains the benchmark harness, which times the phases of a solve
+// over repeated runs and compares the results against a saved baseline.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Phases of a solve which are timed separately, in the order they are run.
+
+var benchPhases = []string{"read", "tighten", "reduce", "solve", "total"}
+
+// Time differences below this are treated as noise when looking for regressions.
+
+const benchMinDiff = 0.010
+
+// benchStats holds the timing statistics of one phase, in seconds.
+type benchStats struct {
+	Median  float64    // median time
+	P90     float64    // 90th percentile
+	Min     float64    // fastest run
+	Max     float64    // slowest run
+	Samples []float64  // time of every run
+}
+
+// benchModel holds the results of benchmarking one model.
+type benchModel struct {
+	File   string                 // MPS file benchmarked
+	Status string                 // "optimal", or the reason a run failed
+	ObjVal float64                // objective value from the last run
+	Phases map[string]benchStats  // statistics for each phase
+}
+
+// benchBaseline holds the results of a benchmark run, as saved to file.
+type benchBaseline struct {
+	Created  string        // date and time the benchmark was run
+	Solver   string        // solver used
+	Runs     int           // number of runs of each model
+	PsCtrl   lpo.PsCtrl    // reductions applied
+	Models   []benchModel  // results for each model
+}
+
+//==============================================================================
+
+// calcBenchStats calculates the statistics for a list of samples, using the
+// nearest-rank method for percentiles.
+func calcBenchStats(samples []float64) benchStats {
+	var stats benchStats  // statistics calculated
+
+	if len(samples) == 0 {
+		return stats
+	}
+
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p * float64(len(sorted)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return sorted[i]
+	}
+
+	stats.Median  = rank(0.5)
+	stats.P90     = rank(0.9)
+	stats.Min     = sorted[0]
+	stats.Max     = sorted[len(sorted)-1]
+	stats.Samples = samples
+
+	return stats
+}
+
+//==============================================================================
+
+// benchOnce runs every phase once on the model in the file specified and
+// returns the time taken by each phase and the objective value. The model is
+// read into the lpo data structures, which TightenBounds changes, so it is read
+// again, untimed, before ReduceMatrix so that this starts from the original
+// model. The solve is performed on the reduced model with no further
+// reductions. In case of failure, function returns an error.
+func benchOnce(ctx context.Context, fileName string, useCoin bool,
+	psCtrl lpo.PsCtrl) (map[string]float64, float64, error) {
+	var times    = make(map[string]float64)  // time of each phase in seconds
+	var soln     lpo.PsSoln                  // solution of the reduced model
+	var tbDone   int                         // TightenBounds iterations completed
+
+	phase := func(name string, start time.Time) {
+		times[name] = time.Since(start).Seconds()
+	}
+
+	start := time.Now()
+	if err := readMpsSense(fileName); err != nil {
+		return nil, 0, errors.Wrap(err, "read failed")
+	}
+	phase("read", start)
+
+	start = time.Now()
+	if err := lpo.TightenBounds(psCtrl.MaxIter, &tbDone); err != nil {
+		return nil, 0, errors.Wrap(err, "TightenBounds failed")
+	}
+	phase("tighten", start)
+
+	if err := readMpsSense(fileName); err != nil {
+		return nil, 0, errors.Wrap(err, "read failed")
+	}
+
+	if ctx.Err() != nil {
+		return nil, 0, abortError(ctx)
+	}
+
+	reduceCtrl := psCtrl
+	reduceCtrl.RunSolver = false
+	reduceCtrl.FileInMps = ""
+	start = time.Now()
+	if err := reduceMatrixSense(reduceCtrl); err != nil {
+		return nil, 0, errors.Wrap(err, "ReduceMatrix failed")
+	}
+	phase("reduce", start)
+
+	solveCtrl := lpo.PsCtrl{RunSolver: true, MaxIter: psCtrl.MaxIter}
+	start = time.Now()
+	if err := solveInContext(ctx, useCoin, solveCtrl, &soln); err != nil {
+		return nil, 0, errors.Wrap(err, "solve failed")
+	}
+	phase("solve", start)
+
+	// Total excludes the untimed second read.
+	times["total"] = times["read"] + times["tighten"] + times["reduce"] + times["solve"]
+
+	return times, soln.ObjVal, nil
+}
+
+//==============================================================================
+
+// runBench benchmarks every file the number of times specified, and returns the
+// results. A failed run marks the model with the error and skips its remaining
+// runs. An interrupt stops the benchmark after the current run.
+func runBench(files []string, useCoin bool, psCtrl lpo.PsCtrl, runs int) benchBaseline {
+	var bench benchBaseline  // results of the benchmark
+
+	bench.Created = time.Now().Format("2006-01-02 15:04:05")
+	bench.Solver  = "Cplex"
+	if useCoin {
+		bench.Solver = "Coin-OR"
+	}
+	bench.Runs   = runs
+	bench.PsCtrl = psCtrl
+
+	ctx, endSolve := beginSolve(0)
+	defer endSolve()
+
+	for _, fileName := range files {
+		model   := benchModel{File: fileName, Status: "optimal"}
+		samples := make(map[string][]float64)
+
+		for run := 1; run <= runs; run++ {
+			if ctx.Err() != nil {
+				model.Status = "interrupted"
+				break
+			}
+
+			times, objVal, err := benchOnce(ctx, fileName, useCoin, psCtrl)
+			if err != nil {
+				model.Status = "failed: " + err.Error()
+				break
+			}
+
+			for _, name := range benchPhases {
+				samples[name] = append(samples[name], times[name])
+			}
+			model.ObjVal = objVal
+			fmt.Printf("%s run %d/%d: total %.3fs, objective %e\n", filepath.Base(fileName),
+				run, runs, times["total"], objVal)
+		}
+
+		model.Phases = make(map[string]benchStats)
+		for _, name := range benchPhases {
+			model.Phases[name] = calcBenchStats(samples[name])
+		}
+		bench.Models = append(bench.Models, model)
+	}
+
+	return bench
+}
+
+//==============================================================================
+
+// printBench prints the median and 90th percentile of each phase for every
+// model benchmarked. The function returns no values.
+func printBench(bench benchBaseline) {
+
+	fmt.Printf("\nBenchmark of %d runs, times in seconds as median/p90:\n", bench.Runs)
+	fmt.Printf("%-24s", "FILE")
+	for _, name := range benchPhases {
+		fmt.Printf(" %17s", name)
+	}
+	fmt.Printf(" %15s  %s\n", "OBJECTIVE", "STATUS")
+
+	for _, model := range bench.Models {
+		fmt.Printf("%-24s", filepath.Base(model.File))
+		for _, name := range benchPhases {
+			stats := model.Phases[name]
+			fmt.Printf(" %8.3f/%-8.3f", stats.Median, stats.P90)
+		}
+		fmt.Printf(" %15e  %s\n", model.ObjVal, firstLine(model.Status))
+	}
+
+}
+
+//==============================================================================
+
+// compareBench compares a benchmark against a baseline and prints every phase
+// whose median time grew by more than the threshold fraction, and every model
+// whose objective value differs by more than the relative tolerance. Models are
+// matched by file name without the directory. The function returns the number
+// of regressions found.
+func compareBench(bench, base benchBaseline, threshold, objTol float64) int {
+	var found int  // number of regressions found
+
+	baseModels := make(map[string]benchModel)
+	for _, model := range base.Models {
+		baseModels[filepath.Base(model.File)] = model
+	}
+
+	fmt.Printf("\nComparison against baseline of %s (%s):\n", base.Created, base.Solver)
+	if base.Solver != bench.Solver ||
+		base.PsCtrl.DelRowNonbinding != bench.PsCtrl.DelRowNonbinding ||
+		base.PsCtrl.DelRowSingleton  != bench.PsCtrl.DelRowSingleton  ||
+		base.PsCtrl.DelColSingleton  != bench.PsCtrl.DelColSingleton  ||
+		base.PsCtrl.DelFixedVars     != bench.PsCtrl.DelFixedVars {
+		fmt.Printf("WARNING: Baseline used a different solver or reductions.\n")
+	}
+
+	for _, model := range bench.Models {
+		name := filepath.Base(model.File)
+		old, ok := baseModels[name]
+		if !ok {
+			fmt.Printf("%-24s not in baseline\n", name)
+			continue
+		}
+
+		if model.Status != old.Status {
+			fmt.Printf("%-24s STATUS    was '%s', now '%s'\n", name,
+				firstLine(old.Status), firstLine(model.Status))
+			found++
+			continue
+		}
+
+		for _, phase := range benchPhases {
+			was := old.Phases[phase].Median
+			now := model.Phases[phase].Median
+			if now - was > benchMinDiff && now > was * (1 + threshold) {
+				fmt.Printf("%-24s SLOWER    %-8s median %.3fs -> %.3fs (%+.1f%%)\n", name,
+					phase, was, now, 100 * (now - was) / was)
+				found++
+			}
+		}
+
+		drift := math.Abs(model.ObjVal - old.ObjVal)
+		if drift > objTol * math.Max(1, math.Abs(old.ObjVal)) {
+			fmt.Printf("%-24s OBJECTIVE changed %.12e -> %.12e\n", name, old.ObjVal, model.ObjVal)
+			found++
+		}
+	}
+
+	if found == 0 {
+		fmt.Printf("No regressions found.\n")
+	}
+
+	return found
+}
+
+//==============================================================================
+
+// wpBench benchmarks a list of models, timing reading, TightenBounds,
+// ReduceMatrix and the solve separately over a number of runs. The results may
+// be saved as a JSON baseline, and may be compared against a baseline saved
+// earlier. Runs are done one at a time in this process so that timings are not
+// disturbed by other solves. In case of failure, function returns an error.
+func wpBench() error {
+	var pattern    string         // directory or glob of files to benchmark
+	var userString string         // input provided by user
+	var fileBase   string         // baseline to compare against
+	var fileSave   string         // file to save results to
+	var runs       int            // number of runs of each model
+	var threshold  float64        // fraction by which a time may grow
+	var objTol     float64        // relative tolerance on objective values
+	var psCtrl     lpo.PsCtrl     // reductions to apply
+	var base       benchBaseline  // baseline read from file
+
+	pattern = promptArg(0, "Enter directory or glob of MPS files: ")
+	if custEnvOn {
+		pattern = dSrcDev + pattern
+	}
+
+	files, err := batchFiles(pattern)
+	if err != nil {
+		return errors.Wrap(err, "wpBench failed")
+	}
+	if len(files) == 0 {
+		return errors.Errorf("wpBench found no MPS files in '%s'", pattern)
+	}
+
+	useCoin := wpGetUseCoin()
+	wpGetPsFlags(&psCtrl)
+	psCtrl.RunSolver = true
+	psCtrl.MaxIter   = paramMaxIter(10)
+
+	runs = 5
+	fmt.Printf("Enter number of runs per model or <CR> for %d: ", runs)
+	fmt.Scanln(&userString)
+	if userString != "" {
+		if runs, err = strconv.Atoi(userString); err != nil || runs < 1 {
+			return errors.Errorf("'%s' is not a valid number of runs.", userString)
+		}
+	}
+
+	fmt.Printf("Enter baseline file to compare against or <CR> for none: ")
+	fmt.Scanln(&fileBase)
+	if fileBase != "" {
+		data, err := ioutil.ReadFile(fileBase)
+		if err == nil {
+			err = json.Unmarshal(data, &base)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "wpBench cannot read baseline %s", fileBase)
+		}
+
+		threshold = 0.10
+		userString = ""
+		fmt.Printf("Enter allowed time increase in percent or <CR> for %.0f: ", 100 * threshold)
+		fmt.Scanln(&userString)
+		if userString != "" {
+			if threshold, err = strconv.ParseFloat(userString, 64); err != nil || threshold < 0 {
+				return errors.Errorf("'%s' is not a valid percentage.", userString)
+			}
+			threshold /= 100
+		}
+
+		objTol = 1.0e-9
+		userString = ""
+		fmt.Printf("Enter relative objective tolerance or <CR> for %g: ", objTol)
+		fmt.Scanln(&userString)
+		if userString != "" {
+			if objTol, err = strconv.ParseFloat(userString, 64); err != nil || objTol < 0 {
+				return errors.Errorf("'%s' is not a valid tolerance.", userString)
+			}
+		}
+	}
+
+	fmt.Printf("Enter file to save results as baseline or <CR> for none: ")
+	fmt.Scanln(&fileSave)
+
+	// Each run reads its model into the data structures, so the model, sense and
+	// solution held before the bench are put back once it is over.
+	model, sense, soln := saveModel(), objSense, psResult
+	defer func() {
+		restoreModel(model)
+		objSense, psResult = sense, soln
+	}()
+
+	fmt.Printf("\nBenchmarking %d models, %d runs each, press Ctrl-C to stop.\n", len(files), runs)
+	bench := runBench(files, useCoin, psCtrl, runs)
+	printBench(bench)
+
+	if fileBase != "" {
+		compareBench(bench, base, threshold, objTol)
+	}
+
+	if fileSave != "" {
+		data, err := json.MarshalIndent(bench, "", "  ")
+		if err == nil {
+			err = ioutil.WriteFile(fileSave, data, 0644)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "wpBench cannot save baseline %s", fileSave)
+		}
+		fmt.Printf("\nResults saved to '%s'.\n", fileSave)
+	}
+
+	return nil
+}
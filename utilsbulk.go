@@ -0,0 +1,377 @@
+// This file contains the bulk deletion of rows or columns of the model held in
+// the lpo data structures, selected by name, type, number of elements or bounds.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Number of names listed in the preview of a bulk deletion.
+
+const bulkPreview = 20
+
+// bulkTest tests whether a row or column, given by its index, meets one
+// condition of a bulk selection.
+type bulkTest func(model lpModel, index int) bool
+
+//==============================================================================
+
+// constraintElems returns the number of elements of a column outside the
+// objective row.
+func constraintElems(model lpModel, iCol int) int {
+	var count int  // elements found
+
+	for _, iElem := range model.Cols[iCol].HasElems {
+		if model.Elems[iElem].InRow != model.ObjRow {
+			count++
+		}
+	}
+
+	return count
+}
+
+//==============================================================================
+
+// compareValue returns the result of comparing the value with the operand using
+// the operator <, <=, >, >=, = or !=.
+func compareValue(value float64, op string, operand float64) bool {
+
+	switch op {
+	case "<":
+		return value < operand
+	case "<=":
+		return value <= operand
+	case ">":
+		return value > operand
+	case ">=":
+		return value >= operand
+	case "=":
+		return value == operand
+	default:
+		return value != operand
+	}
+}
+
+//==============================================================================
+
+// bulkTests returns the conditions described by the fields, all of which a row
+// or column must meet to be selected:
+//   name pattern       name matches the pattern, as for path.Match
+//   regex expression   name matches the regular expression
+//   type t             row type (N, E, L, G) or column type (C, I, B)
+//   empty              no elements, outside the objective for a column
+//   singleton          one element, outside the objective for a column
+//   fixed              equal limits or bounds
+//   free               infinite limits or bounds
+//   lo|up op value     lower or upper limit or bound compared with a value,
+//                      where op is <, <=, >, >=, = or !=
+//   rhs op value       right-hand side of a row compared with a value
+// In case of failure, function returns an error.
+func bulkTests(fields []string, isRow bool) ([]bulkTest, error) {
+	var tests []bulkTest  // conditions found
+
+	if len(fields) == 0 {
+		return nil, errors.New("no selection given")
+	}
+
+	limits := func(model lpModel, index int) (float64, float64) {
+		if isRow {
+			return model.Rows[index].RHSlo, model.Rows[index].RHSup
+		}
+		return model.Cols[index].BndLo, model.Cols[index].BndUp
+	}
+	name := func(model lpModel, index int) string {
+		if isRow {
+			return model.Rows[index].Name
+		}
+		return model.Cols[index].Name
+	}
+	elems := func(model lpModel, index int) int {
+		if isRow {
+			return len(model.Rows[index].HasElems)
+		}
+		return constraintElems(model, index)
+	}
+
+	for k := 0; k < len(fields); k++ {
+		arg := func() (string, error) {
+			if k + 1 >= len(fields) {
+				return "", errors.Errorf("'%s' needs an argument", fields[k])
+			}
+			k++
+			return fields[k], nil
+		}
+
+		switch strings.ToLower(fields[k]) {
+		case "name":
+			pattern, err := arg()
+			if err != nil {
+				return nil, err
+			}
+			if _, err = path.Match(pattern, ""); err != nil {
+				return nil, errors.Errorf("invalid name pattern '%s'", pattern)
+			}
+			tests = append(tests, func(model lpModel, index int) bool {
+				ok, _ := path.Match(pattern, name(model, index))
+				return ok
+			})
+
+		case "regex":
+			expr, err := arg()
+			if err != nil {
+				return nil, err
+			}
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, errors.Errorf("invalid regular expression '%s'", expr)
+			}
+			tests = append(tests, func(model lpModel, index int) bool {
+				return re.MatchString(name(model, index))
+			})
+
+		case "type":
+			itemType, err := arg()
+			if err != nil {
+				return nil, err
+			}
+			itemType = strings.ToUpper(itemType)
+			tests = append(tests, func(model lpModel, index int) bool {
+				switch {
+				case isRow:
+					return model.Rows[index].Type == itemType
+				case itemType == "C":
+					return !isIntCol(model.Cols[index])
+				default:
+					return model.Cols[index].Type == itemType
+				}
+			})
+
+		case "empty":
+			tests = append(tests, func(model lpModel, index int) bool {
+				return elems(model, index) == 0
+			})
+
+		case "singleton":
+			tests = append(tests, func(model lpModel, index int) bool {
+				return elems(model, index) == 1
+			})
+
+		case "fixed":
+			tests = append(tests, func(model lpModel, index int) bool {
+				lo, up := limits(model, index)
+				return lo == up
+			})
+
+		case "free":
+			tests = append(tests, func(model lpModel, index int) bool {
+				lo, up := limits(model, index)
+				return isNeginf(lo) && isPlinfy(up)
+			})
+
+		case "lo", "up", "rhs":
+			bound := strings.ToLower(fields[k])
+			if bound == "rhs" && !isRow {
+				return nil, errors.New("'rhs' applies to rows only")
+			}
+			if k + 2 >= len(fields) {
+				return nil, errors.Errorf("'%s' needs an operator and a value", fields[k])
+			}
+			op := fields[k + 1]
+			if op != "<" && op != "<=" && op != ">" && op != ">=" && op != "=" && op != "!=" {
+				return nil, errors.Errorf("invalid operator '%s'", op)
+			}
+			operand, err := parseScenValue(fields[k + 2])
+			if err != nil {
+				return nil, err
+			}
+			k += 2
+			tests = append(tests, func(model lpModel, index int) bool {
+				lo, up := limits(model, index)
+				switch bound {
+				case "rhs":
+					return compareValue(rhsBase(model.Rows[index]), op, operand)
+				case "up":
+					return compareValue(up, op, operand)
+				default:
+					return compareValue(lo, op, operand)
+				}
+			})
+
+		default:
+			return nil, errors.Errorf("unknown selection '%s'", fields[k])
+		}
+	}
+
+	return tests, nil
+}
+
+//==============================================================================
+
+// bulkSelect returns, for each row or column of the model, whether it meets all
+// the conditions. The objective row is never selected.
+func bulkSelect(model lpModel, isRow bool, tests []bulkTest) []bool {
+
+	count := len(model.Cols)
+	if isRow {
+		count = len(model.Rows)
+	}
+
+	selected := make([]bool, count)
+	for index := range selected {
+		if isRow && index == model.ObjRow {
+			continue
+		}
+		selected[index] = true
+		for _, test := range tests {
+			if !test(model, index) {
+				selected[index] = false
+				break
+			}
+		}
+	}
+
+	return selected
+}
+
+//==============================================================================
+
+// deleteItems returns a copy of the model without the rows and columns marked
+// for deletion, and without their elements, keeping the order of the others.
+// The objective row must not be deleted.
+func deleteItems(model lpModel, delRows, delCols []bool) lpModel {
+	var work   lpModel            // model being built
+	var elems  []lpo.InputElem    // elements kept
+	var newRow = make([]int, len(model.Rows))  // new index of each row, -1 if deleted
+	var newCol = make([]int, len(model.Cols))  // new index of each column, -1 if deleted
+
+	work.Name = model.Name
+	for i, row := range model.Rows {
+		newRow[i] = -1
+		if !delRows[i] {
+			newRow[i] = len(work.Rows)
+			work.Rows = append(work.Rows, row)
+		}
+	}
+	for j, col := range model.Cols {
+		newCol[j] = -1
+		if !delCols[j] {
+			newCol[j] = len(work.Cols)
+			work.Cols = append(work.Cols, col)
+		}
+	}
+
+	work.ObjRow = -1
+	if model.ObjRow >= 0 && model.ObjRow < len(model.Rows) {
+		work.ObjRow = newRow[model.ObjRow]
+	}
+
+	for _, elem := range model.Elems {
+		if newRow[elem.InRow] >= 0 && newCol[elem.InCol] >= 0 {
+			elems = append(elems, lpo.InputElem{InRow: newRow[elem.InRow],
+				InCol: newCol[elem.InCol], Value: elem.Value})
+		}
+	}
+	setElems(&work, elems)
+
+	return work
+}
+
+//==============================================================================
+
+// wpBulkDelete deletes the rows or columns of the model held in the lpo data
+// structures which meet the selection given on the command line as "delete
+// rows|cols selection", or entered at the prompt, or with keepOnly set keeps
+// only those and deletes the others. The objective row is always kept. The
+// matches are listed, and the deletion made in one pass once confirmed, then
+// checked with lpo.AdjustModel. In case of failure, function returns an error.
+func wpBulkDelete(keepOnly bool) error {
+	var userString string    // input provided by user
+	var names      []string  // names of the rows or columns deleted
+	var isRow      bool      // rows are deleted rather than columns
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 {
+		return errors.New("wpBulkDelete failed, model not defined")
+	}
+
+	what := strings.ToLower(promptArg(0, "Delete rows or cols: "))
+	switch what {
+	case "rows", "row":
+		isRow, what = true, "rows"
+	case "cols", "col", "columns":
+		what = "columns"
+	default:
+		return errors.Errorf("wpBulkDelete failed, expected rows or cols, not '%s'", what)
+	}
+
+	var selection string  // conditions selecting the rows or columns
+	if len(cmdArgs) > 1 {
+		selection = strings.Join(cmdArgs[1:], " ")
+	} else {
+		fmt.Printf("Enter selection (name|regex|type|empty|singleton|fixed|free|lo|up|rhs ...): ")
+		selection = readLine()
+	}
+	tests, err := bulkTests(strings.Fields(selection), isRow)
+	if err != nil {
+		return errors.Wrap(err, "wpBulkDelete failed")
+	}
+
+	model    := saveModel()
+	selected := bulkSelect(model, isRow, tests)
+	delRows  := make([]bool, len(model.Rows))
+	delCols  := make([]bool, len(model.Cols))
+
+	for index, isSelected := range selected {
+		if isSelected == keepOnly || (isRow && index == model.ObjRow) {
+			continue
+		}
+		if isRow {
+			delRows[index] = true
+			names = append(names, model.Rows[index].Name)
+		} else {
+			delCols[index] = true
+			names = append(names, model.Cols[index].Name)
+		}
+	}
+
+	if len(names) == 0 {
+		fmt.Printf("No %s to delete.\n", what)
+		return nil
+	}
+
+	work := deleteItems(model, delRows, delCols)
+	fmt.Printf("%d of %d %s to delete, removing %d elements:\n", len(names), len(selected), what,
+		len(model.Elems) - len(work.Elems))
+	for k, name := range names {
+		if k == bulkPreview {
+			fmt.Printf("  ... and %d more\n", len(names) - bulkPreview)
+			break
+		}
+		fmt.Printf("  %s\n", name)
+	}
+
+	fmt.Printf("Delete these %d %s [Y|N]: ", len(names), what)
+	fmt.Scanln(&userString)
+	if userString != "y" && userString != "Y" {
+		fmt.Printf("Nothing deleted.\n")
+		return nil
+	}
+
+	restoreModel(work)
+	if err = lpo.AdjustModel(); err != nil {
+		restoreModel(model)
+		return errors.Wrap(err, "wpBulkDelete failed, AdjustModel rejected the result, model left unchanged")
+	}
+	clearLpoSoln()
+
+	fmt.Printf("%d %s deleted, the model has %d rows and %d columns.\n", len(names), what,
+		len(lpo.Rows), len(lpo.Cols))
+
+	return nil
+}
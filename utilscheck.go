@@ -0,0 +1,244 @@
+// This file contains the differential check of ReduceMatrix, which compares
+// solves of the same model with and without each matrix reduction.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// psCheckRun describes one solve made by the presolve check, and its outcome.
+type psCheckRun struct {
+	Name     string        // name of the reductions applied
+	PsCtrl   lpo.PsCtrl    // control structure used for the solve
+	Err      error         // error returned by the solve
+	Soln     lpo.PsSoln    // solution obtained
+	ObjDiff  float64       // difference in objective from the unreduced solve
+	VarDiff  float64       // largest difference in a variable value
+	VarName  string        // variable with the largest difference
+	Verify   verifyReport  // verification against the original model
+	Result   string        // "OK", "WARN" or "FAIL"
+}
+
+//==============================================================================
+
+// psCheckRuns returns the list of solves made by the presolve check: no
+// reductions, each reduction alone, and all reductions together.
+func psCheckRuns() []psCheckRun {
+	var runs []psCheckRun  // runs to be made
+
+	runs = append(runs, psCheckRun{Name: "none"})
+	runs = append(runs, psCheckRun{Name: "DelRowNonbinding", PsCtrl: lpo.PsCtrl{DelRowNonbinding: true}})
+	runs = append(runs, psCheckRun{Name: "DelRowSingleton",  PsCtrl: lpo.PsCtrl{DelRowSingleton:  true}})
+	runs = append(runs, psCheckRun{Name: "DelColSingleton",  PsCtrl: lpo.PsCtrl{DelColSingleton:  true}})
+	runs = append(runs, psCheckRun{Name: "DelFixedVars",     PsCtrl: lpo.PsCtrl{DelFixedVars:     true}})
+	runs = append(runs, psCheckRun{Name: "all", PsCtrl: lpo.PsCtrl{DelRowNonbinding: true,
+		DelRowSingleton: true, DelColSingleton: true, DelFixedVars: true}})
+
+	return runs
+}
+
+//==============================================================================
+
+// compareSolutions returns the difference in objective value between the
+// solution and the reference, and the largest difference in the value of any
+// variable of the reference, together with that variable's name. A variable
+// missing from the solution counts as an infinite difference.
+func compareSolutions(soln, ref lpo.PsSoln) (float64, float64, string) {
+	var maxDiff float64  // largest difference found
+	var maxName string   // variable with the largest difference
+
+	for name, refVarb := range ref.VarMap {
+		diff := math.Inf(1)
+		if varb, ok := soln.VarMap[name]; ok {
+			diff = math.Abs(varb.Value - refVarb.Value)
+		}
+		if maxName == "" || diff > maxDiff {
+			maxDiff = diff
+			maxName = name
+		}
+	}
+
+	return soln.ObjVal - ref.ObjVal, maxDiff, maxName
+}
+
+//==============================================================================
+
+// wpPresolveCheck solves the model with no reductions, with each reduction of
+// ReduceMatrix on its own, and with all of them, and compares each solution with
+// the unreduced one. A run fails if its objective value differs by more than the
+// tolerance, or its solution violates the original model; a run whose objective
+// agrees but whose variable values differ is only flagged, since the model may
+// have several optimal solutions. The PSOP file of every run which fails is
+// kept. In case of failure, function returns an error.
+func wpPresolveCheck() error {
+	var fileName   string       // MPS file holding the model, empty for data structures
+	var saveDir    string       // directory for PSOP files of failed runs
+	var userString string       // input provided by user
+	var tol        float64      // tolerance for comparisons
+	var model      lpModel      // model being checked
+	var blamed     []string     // reductions whose runs failed
+	var err        error        // error returned by functions called
+
+	fileName = promptArg(0, "Enter MPS file name or <CR> to use data structures: ")
+	if fileName != "" {
+		if custEnvOn {
+			fileName = dSrcDev + fileName + fExtension
+		}
+
+		// The model of the file, with its own objective sense, is checked, and
+		// the model, sense and solution held before are put back afterwards.
+		caller, sense, soln := saveModel(), objSense, psResult
+		defer func() {
+			restoreModel(caller)
+			objSense, psResult = sense, soln
+		}()
+		if err = readMpsSense(fileName); err != nil {
+			return errors.Wrapf(err, "wpPresolveCheck cannot read model from %s", fileName)
+		}
+		model = saveModel()
+	} else {
+		if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+			return errors.New("wpPresolveCheck failed, model not defined")
+		}
+		model = saveModel()
+	}
+
+	useCoin := wpGetUseCoin()
+
+	tol = verifyTol
+	fmt.Printf("Enter tolerance or <CR> for %g: ", tol)
+	fmt.Scanln(&userString)
+	if userString != "" {
+		if tol, err = strconv.ParseFloat(userString, 64); err != nil || tol < 0 {
+			return errors.Errorf("'%s' is not a valid tolerance.", userString)
+		}
+	}
+
+	fmt.Printf("Enter directory for PSOP files of failed runs or <CR> for current: ")
+	fmt.Scanln(&saveDir)
+	if custEnvOn && saveDir == "" {
+		saveDir = dSrcDev
+	}
+
+	workDir, err := ioutil.TempDir("", "runopt_check")
+	if err != nil {
+		return errors.Wrap(err, "wpPresolveCheck cannot create work directory")
+	}
+	defer os.RemoveAll(workDir)
+
+	// Every run starts from the original model, and the data structures are
+	// left holding the model they held before the check.
+	if fileName == "" {
+		defer restoreModel(model)
+	}
+
+	ctx, endSolve := beginSolve(0)
+	defer endSolve()
+
+	runs := psCheckRuns()
+	for i := range runs {
+		run := &runs[i]
+
+		run.PsCtrl.RunSolver   = true
+		run.PsCtrl.MaxIter     = paramMaxIter(10)
+		run.PsCtrl.FileOutPsop = filepath.Join(workDir, "psop_" + run.Name + ".txt")
+
+		fmt.Printf("Solving with reductions: %s\n", run.Name)
+		restoreModel(model)
+		run.Err = solveInContext(ctx, useCoin, run.PsCtrl, &run.Soln)
+		if run.Err == errInterrupted {
+			return errors.Wrap(run.Err, "wpPresolveCheck failed")
+		}
+		if run.Err == nil {
+			run.Verify = verifySolution(model, run.Soln, tol)
+		}
+	}
+
+	if runs[0].Err != nil {
+		return errors.Wrap(runs[0].Err, "wpPresolveCheck failed, unreduced model not solved")
+	}
+
+	for i := range runs {
+		run := &runs[i]
+
+		switch {
+		case run.Err != nil:
+			run.Result = "FAIL"
+		default:
+			run.ObjDiff, run.VarDiff, run.VarName = compareSolutions(run.Soln, runs[0].Soln)
+			run.Result = "OK"
+			if run.VarDiff > tol * math.Max(1, math.Abs(runs[0].Soln.VarMap[run.VarName].Value)) {
+				run.Result = "WARN"
+			}
+			if math.Abs(run.ObjDiff) > tol * math.Max(1, math.Abs(runs[0].Soln.ObjVal)) ||
+				!run.Verify.Pass {
+				run.Result = "FAIL"
+			}
+		}
+
+		if run.Result == "FAIL" && i > 0 {
+			blamed = append(blamed, run.Name)
+		}
+	}
+
+	fmt.Printf("\n%-18s %15s %13s %13s %-16s %-8s %s\n", "REDUCTIONS", "OBJECTIVE",
+		"OBJ DIFF", "MAX VAR DIFF", "VARIABLE", "VERIFY", "RESULT")
+	for _, run := range runs {
+		if run.Err != nil {
+			fmt.Printf("%-18s %s: %s\n", run.Name, run.Result, firstLine(run.Err.Error()))
+			continue
+		}
+		verified := "pass"
+		if !run.Verify.Pass {
+			verified = "fail"
+		}
+		fmt.Printf("%-18s %15e %13e %13e %-16s %-8s %s\n", run.Name, run.Soln.ObjVal,
+			run.ObjDiff, run.VarDiff, run.VarName, verified, run.Result)
+	}
+
+	if len(blamed) == 0 {
+		fmt.Printf("\nAll reductions agree with the unreduced solve.\n")
+		return nil
+	}
+
+	// If only the combined run failed, the reductions are wrong in combination.
+	if len(blamed) == 1 && blamed[0] == "all" {
+		fmt.Printf("\nReductions agree individually but fail when combined.\n")
+	} else {
+		fmt.Printf("\nReductions to blame:")
+		for _, name := range blamed {
+			if name != "all" {
+				fmt.Printf(" %s", name)
+			}
+		}
+		fmt.Printf("\n")
+	}
+
+	for _, run := range runs {
+		if run.Result != "FAIL" || run.Name == "none" {
+			continue
+		}
+		data, err := ioutil.ReadFile(run.PsCtrl.FileOutPsop)
+		if err != nil {
+			fmt.Printf("No PSOP file was written for %s.\n", run.Name)
+			continue
+		}
+		target := filepath.Join(saveDir, fPrefPsopOut + "check_" + run.Name + fExtension)
+		if err = ioutil.WriteFile(target, data, 0644); err != nil {
+			fmt.Println(err)
+		} else {
+			fmt.Printf("PSOP file for %s saved to '%s'.\n", run.Name, target)
+		}
+	}
+
+	return nil
+}
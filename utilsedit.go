@@ -0,0 +1,563 @@
+// This file contains the model editor, which adds and changes rows, columns,
+// coefficients and bounds of the model held in the lpo data structures by name,
+// accepting rows written as equations.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// eqRow is a row read from an equation such as "c5: 3 x1 + 2 x2 <= 10".
+type eqRow struct {
+	Name  string              // row name
+	Type  string              // row type: N, E, L or G
+	RHSlo float64             // lower limit
+	RHSup float64             // upper limit
+	Cols  []string            // columns, in order of appearance
+	Coefs map[string]float64  // coefficient of each column
+}
+
+// eqSide is one side of an equation: a linear expression plus a constant.
+type eqSide struct {
+	Cols   []string            // columns, in order of appearance
+	Coefs  map[string]float64  // coefficient of each column
+	Const  float64             // constant term
+}
+
+//==============================================================================
+
+// isDigit returns true if the character is a decimal digit.
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+//==============================================================================
+
+// eqTokens splits an equation, without its name, into numbers, names, the
+// operators +, - and *, and the relations <=, >= and =. Names end at white
+// space or at any of the characters "+-*<>=", which they therefore cannot hold.
+// In case of failure, function returns an error.
+func eqTokens(text string) ([]string, error) {
+	var tokens []string  // tokens found
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+
+		case c == '+' || c == '-' || c == '*':
+			tokens = append(tokens, string(c))
+			i++
+
+		case c == '<' || c == '>' || c == '=':
+			j := i + 1
+			for j < len(text) && strings.IndexByte("<>=", text[j]) >= 0 {
+				j++
+			}
+			switch text[i:j] {
+			case "<=", "=<":
+				tokens = append(tokens, "<=")
+			case ">=", "=>":
+				tokens = append(tokens, ">=")
+			case "=", "==":
+				tokens = append(tokens, "=")
+			default:
+				return nil, errors.Errorf("invalid relation '%s'", text[i:j])
+			}
+			i = j
+
+		case isDigit(c) || c == '.':
+			j := i
+			for j < len(text) && (isDigit(text[j]) || text[j] == '.') {
+				j++
+			}
+			// An exponent is only taken if digits follow, so that "2e" reads as
+			// 2 times the column e.
+			if j < len(text) && (text[j] == 'e' || text[j] == 'E') {
+				k := j + 1
+				if k < len(text) && (text[k] == '+' || text[k] == '-') {
+					k++
+				}
+				if k < len(text) && isDigit(text[k]) {
+					for j = k; j < len(text) && isDigit(text[j]); j++ {
+					}
+				}
+			}
+			tokens = append(tokens, text[i:j])
+			i = j
+
+		default:
+			j := i
+			for j < len(text) && strings.IndexByte(" \t+-*<>=", text[j]) < 0 {
+				j++
+			}
+			tokens = append(tokens, text[i:j])
+			i = j
+		}
+	}
+
+	return tokens, nil
+}
+
+//==============================================================================
+
+// parseSide returns the linear expression made up of the tokens, which are
+// terms such as "3 x1", "3 * x1", "- x2", "+ 4" or "inf", each but the first
+// starting with a sign. In case of failure, function returns an error.
+func parseSide(tokens []string) (eqSide, error) {
+
+	side := eqSide{Coefs: make(map[string]float64)}
+	if len(tokens) == 0 {
+		return side, errors.New("missing expression")
+	}
+
+	for k := 0; k < len(tokens); {
+		sign := 1.0
+		if tokens[k] == "+" || tokens[k] == "-" {
+			if tokens[k] == "-" {
+				sign = -1.0
+			}
+			k++
+		} else if k > 0 {
+			return side, errors.Errorf("expected + or - before '%s'", tokens[k])
+		}
+		if k >= len(tokens) {
+			return side, errors.New("expression ends with a sign")
+		}
+
+		// A coefficient starts with a digit or a point, so that names such as
+		// NaN or Inf, which ParseFloat accepts, are read as columns. Only "inf"
+		// standing alone as a term is an infinite constant.
+		coef, hasCoef := 1.0, false
+		token := tokens[k]
+		isNumber := isDigit(token[0]) || token[0] == '.'
+		isInf := token == "inf" &&
+			(k + 1 >= len(tokens) || tokens[k + 1] == "+" || tokens[k + 1] == "-")
+		if value, err := strconv.ParseFloat(token, 64); err == nil && (isNumber || isInf) {
+			coef, hasCoef = value, true
+			k++
+			if k < len(tokens) && tokens[k] == "*" {
+				k++
+				if k >= len(tokens) || tokens[k] == "+" || tokens[k] == "-" {
+					return side, errors.New("expected a column after '*'")
+				}
+			}
+		}
+
+		if k >= len(tokens) || tokens[k] == "+" || tokens[k] == "-" {
+			if !hasCoef {
+				return side, errors.New("missing term")
+			}
+			side.Const += sign * coef
+			continue
+		}
+
+		name := tokens[k]
+		if name == "*" {
+			return side, errors.New("unexpected '*'")
+		}
+		if _, seen := side.Coefs[name]; !seen {
+			side.Cols = append(side.Cols, name)
+		}
+		side.Coefs[name] += sign * coef
+		k++
+	}
+
+	return side, nil
+}
+
+//==============================================================================
+
+// parseEquation returns the row described by an equation of the form
+//   name: expression                       (a free row)
+//   name: expression rel expression        (rel is <=, >= or =)
+//   name: lo <= expression <= up           (a ranged row)
+// Columns may appear on both sides of a relation, and are moved to the left,
+// and constants to the right. "inf" and "-inf" may be used as limits, so a
+// column named inf can only appear in an equation with a coefficient.
+// In case of failure, function returns an error.
+func parseEquation(text string) (eqRow, error) {
+	var parts [][]string  // tokens between relations
+	var rels  []string    // relations found
+
+	colon := strings.Index(text, ":")
+	if colon < 0 {
+		return eqRow{}, errors.New("expected 'name: expression'")
+	}
+	eq := eqRow{Name: strings.TrimSpace(text[:colon]), Coefs: make(map[string]float64),
+		RHSlo: lpo.Neginf, RHSup: lpo.Plinfy}
+	if eq.Name == "" || strings.ContainsAny(eq.Name, " \t") {
+		return eq, errors.Errorf("invalid row name '%s'", eq.Name)
+	}
+
+	tokens, err := eqTokens(text[colon + 1:])
+	if err != nil {
+		return eq, err
+	}
+	parts = append(parts, nil)
+	for _, token := range tokens {
+		if token == "<=" || token == ">=" || token == "=" {
+			rels  = append(rels, token)
+			parts = append(parts, nil)
+			continue
+		}
+		parts[len(parts) - 1] = append(parts[len(parts) - 1], token)
+	}
+
+	var sides []eqSide  // expressions between relations
+	for _, part := range parts {
+		side, err := parseSide(part)
+		if err != nil {
+			return eq, err
+		}
+		sides = append(sides, side)
+	}
+
+	addSide := func(side eqSide, sign float64) {
+		for _, name := range side.Cols {
+			if _, seen := eq.Coefs[name]; !seen {
+				eq.Cols = append(eq.Cols, name)
+			}
+			eq.Coefs[name] += sign * side.Coefs[name]
+		}
+	}
+
+	switch len(rels) {
+	case 0:
+		if sides[0].Const != 0 {
+			return eq, errors.New("a free row cannot have a constant")
+		}
+		eq.Type = "N"
+		addSide(sides[0], 1)
+
+	case 1:
+		rhs := sides[1].Const - sides[0].Const
+		addSide(sides[0], 1)
+		addSide(sides[1], -1)
+		switch rels[0] {
+		case "<=":
+			eq.Type, eq.RHSup = "L", rhs
+		case ">=":
+			eq.Type, eq.RHSlo = "G", rhs
+		default:
+			eq.Type, eq.RHSlo, eq.RHSup = "E", rhs, rhs
+		}
+
+	case 2:
+		if len(sides[0].Cols) > 0 || len(sides[2].Cols) > 0 || rels[0] != rels[1] || rels[0] == "=" {
+			return eq, errors.New("expected 'lo <= expression <= up' for a ranged row")
+		}
+		eq.RHSlo, eq.RHSup = sides[0].Const - sides[1].Const, sides[2].Const - sides[1].Const
+		if rels[0] == ">=" {
+			eq.RHSlo, eq.RHSup = eq.RHSup, eq.RHSlo
+		}
+		if eq.RHSlo > eq.RHSup {
+			return eq, errors.Errorf("lower limit %g exceeds upper limit %g", eq.RHSlo, eq.RHSup)
+		}
+		switch {
+		case eq.RHSlo == eq.RHSup:
+			eq.Type = "E"
+		case isPlinfy(eq.RHSup):
+			eq.Type = "G"
+		default:
+			eq.Type = "L"
+		}
+		addSide(sides[1], 1)
+
+	default:
+		return eq, errors.New("too many relations")
+	}
+
+	// Columns whose coefficients cancel out are dropped.
+	var cols []string  // columns kept
+	for _, name := range eq.Cols {
+		if eq.Coefs[name] != 0 {
+			cols = append(cols, name)
+		}
+	}
+	eq.Cols = cols
+
+	// Infinite limits are stored as lpo stores them.
+	for _, limit := range []*float64{&eq.RHSlo, &eq.RHSup} {
+		switch {
+		case isPlinfy(*limit):
+			*limit = lpo.Plinfy
+		case isNeginf(*limit):
+			*limit = lpo.Neginf
+		}
+	}
+
+	return eq, nil
+}
+
+//==============================================================================
+
+// setEqRow adds the row read from an equation to the model, or replaces the
+// coefficients and limits of the row if it exists. Columns which do not exist
+// are added as continuous columns with bounds 0 and infinity. The function
+// returns the names of the columns added. In case of failure, function returns
+// an error.
+func setEqRow(work *lpModel, eq eqRow) ([]string, error) {
+	var added []string  // columns added
+
+	iRow := findRow(*work, eq.Name)
+	if iRow >= 0 && iRow == work.ObjRow && eq.Type != "N" {
+		return nil, errors.Errorf("row '%s' is the objective function", eq.Name)
+	}
+
+	if iRow < 0 {
+		iRow = len(work.Rows)
+		work.Rows = append(work.Rows, lpo.InputRow{Name: eq.Name})
+	} else {
+		var elems []lpo.InputElem  // elements of the other rows
+		for _, elem := range work.Elems {
+			if elem.InRow != iRow {
+				elems = append(elems, elem)
+			}
+		}
+		setElems(work, elems)
+	}
+
+	row := &work.Rows[iRow]
+	row.Type, row.RHSlo, row.RHSup = eq.Type, eq.RHSlo, eq.RHSup
+
+	contType := contColType(*work)
+	for _, name := range eq.Cols {
+		iCol := findCol(*work, name)
+		if iCol < 0 {
+			iCol = len(work.Cols)
+			work.Cols = append(work.Cols, lpo.InputCol{Name: name, Type: contType, BndLo: 0,
+				BndUp: lpo.Plinfy})
+			added = append(added, name)
+		}
+		setCoef(work, iRow, iCol, eq.Coefs[name])
+	}
+
+	// A model without an objective function takes the first free row added.
+	if eq.Type == "N" && (work.ObjRow < 0 || work.ObjRow >= len(work.Rows) ||
+		!isFreeRow(work.Rows[work.ObjRow])) {
+		work.ObjRow = iRow
+	}
+
+	return added, nil
+}
+
+//==============================================================================
+
+// addEditCol adds a column to the model from the fields "col name [lo [up
+// [type]]]", where type is C (continuous, the default), I (integer) or B
+// (binary, with default bounds 0 and 1). In case of failure, function returns
+// an error.
+func addEditCol(work *lpModel, fields []string) error {
+	var err error  // error returned by functions called
+
+	if len(fields) < 2 || len(fields) > 5 {
+		return errors.New("expected 'col name [lo [up [C|I|B]]]'")
+	}
+	if findCol(*work, fields[1]) >= 0 {
+		return errors.Errorf("column '%s' already exists", fields[1])
+	}
+
+	col := lpo.InputCol{Name: fields[1], Type: contColType(*work), BndLo: 0, BndUp: lpo.Plinfy}
+	if len(fields) == 5 {
+		switch strings.ToUpper(fields[4]) {
+		case "C":
+		case "I":
+			col.Type = "I"
+		case "B":
+			col.Type, col.BndUp = "B", 1
+		default:
+			return errors.Errorf("unknown column type '%s'", fields[4])
+		}
+	}
+	if len(fields) >= 3 {
+		if col.BndLo, err = parseScenValue(fields[2]); err != nil {
+			return err
+		}
+	}
+	if len(fields) >= 4 {
+		if col.BndUp, err = parseScenValue(fields[3]); err != nil {
+			return err
+		}
+	}
+	if col.BndLo > col.BndUp {
+		return errors.Errorf("lower bound %g exceeds upper bound %g", col.BndLo, col.BndUp)
+	}
+
+	work.Cols = append(work.Cols, col)
+	return nil
+}
+
+//==============================================================================
+
+// renameEdit renames a row or column from the fields "rename row|col old new".
+// In case of failure, function returns an error.
+func renameEdit(work *lpModel, fields []string) error {
+
+	if len(fields) != 4 {
+		return errors.New("expected 'rename row|col old new'")
+	}
+	oldName, newName := fields[2], fields[3]
+
+	switch strings.ToLower(fields[1]) {
+	case "row":
+		iRow := findRow(*work, oldName)
+		if iRow < 0 {
+			return errors.Errorf("row '%s' not found", oldName)
+		}
+		if findRow(*work, newName) >= 0 {
+			return errors.Errorf("row '%s' already exists", newName)
+		}
+		work.Rows[iRow].Name = newName
+
+	case "col":
+		iCol := findCol(*work, oldName)
+		if iCol < 0 {
+			return errors.Errorf("column '%s' not found", oldName)
+		}
+		if findCol(*work, newName) >= 0 {
+			return errors.Errorf("column '%s' already exists", newName)
+		}
+		work.Cols[iCol].Name = newName
+
+	default:
+		return errors.New("expected 'rename row|col old new'")
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// editModel applies one editor command to the model. The commands are:
+//   row equation, or just the equation    (add or replace a row)
+//   col name [lo [up [C|I|B]]]            (add a column)
+//   coef row column value                 (set a coefficient)
+//   bound column lo|up|fix|free [value]   (set a bound)
+//   rhs row value                         (set a right-hand side)
+//   rename row|col old new                (rename a row or column)
+// The function returns a message describing the change.
+// In case of failure, function returns an error.
+func editModel(work *lpModel, line string) (string, error) {
+
+	fields := strings.Fields(line)
+	command := strings.ToLower(fields[0])
+
+	switch command {
+	case "col":
+		if err := addEditCol(work, fields); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Column '%s' added.", fields[1]), nil
+
+	case "coef", "bound", "rhs":
+		edit, err := parseScenEdit(fields)
+		if err != nil {
+			return "", err
+		}
+		if err = applyEdit(work, edit); err != nil {
+			return "", err
+		}
+		if edit.Row != "" {
+			return fmt.Sprintf("Row '%s' changed.", edit.Row), nil
+		}
+		return fmt.Sprintf("Column '%s' changed.", edit.Col), nil
+
+	case "rename":
+		if err := renameEdit(work, fields); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Renamed '%s' to '%s'.", fields[2], fields[3]), nil
+	}
+
+	if command == "row" {
+		line = strings.TrimSpace(line)[len(fields[0]):]
+	} else if !strings.Contains(line, ":") {
+		return "", errors.Errorf("unknown edit '%s'", fields[0])
+	}
+
+	eq, err := parseEquation(line)
+	if err != nil {
+		return "", err
+	}
+	exists := findRow(*work, eq.Name) >= 0
+	added, err := setEqRow(work, eq)
+	if err != nil {
+		return "", err
+	}
+
+	message := fmt.Sprintf("Row '%s' added", eq.Name)
+	if exists {
+		message = fmt.Sprintf("Row '%s' replaced", eq.Name)
+	}
+	if len(added) > 0 {
+		message += fmt.Sprintf(", with new columns %s", strings.Join(added, ", "))
+	}
+
+	return message + ".", nil
+}
+
+//==============================================================================
+
+// runEdit applies one editor command to the model held in the lpo data
+// structures, and checks the result with lpo.AdjustModel. If the command or the
+// check fails, the model is left as it was. Otherwise the solutions held, which
+// belong to the previous model, are cleared.
+// In case of failure, function returns an error.
+func runEdit(line string) error {
+
+	model := saveModel()
+	work  := copyModel(model)
+
+	message, err := editModel(&work, line)
+	if err != nil {
+		return errors.Wrap(err, "Edit failed")
+	}
+
+	restoreModel(work)
+	if err = lpo.AdjustModel(); err != nil {
+		restoreModel(model)
+		return errors.Wrap(err, "Edit rejected by AdjustModel, model left unchanged")
+	}
+
+	clearLpoSoln()
+	fmt.Println(message)
+
+	return nil
+}
+
+//==============================================================================
+
+// wpEdit applies the editor command given on the command line, or if there is
+// none, reads commands until an empty line is entered. Each command is applied
+// and checked on its own, so that an error leaves the earlier edits in place.
+// The function returns the error of a command given on the command line.
+func wpEdit() error {
+
+	if len(cmdArgs) > 0 {
+		if err := runEdit(strings.Join(cmdArgs, " ")); err != nil {
+			return errors.Wrap(err, "wpEdit failed")
+		}
+		return nil
+	}
+
+	fmt.Printf("Enter edits, one per line, or <CR> to finish, e.g.\n")
+	fmt.Printf("  c5: 3 x1 + 2 x2 <= 10    col x3 0 inf I    bound x3 up 5\n")
+	for {
+		fmt.Printf("edit> ")
+		line := strings.TrimSpace(readLine())
+		if line == "" {
+			break
+		}
+		if err := runEdit(line); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,280 @@
+// This file contains the elastic reformulation of the model, which adds
+// violation columns to selected rows so that an infeasible model can be solved
+// and the constraints that had to be relaxed identified.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Prefixes of the names of the columns measuring how far a row lies above its
+// upper limit and below its lower limit.
+
+const elasticPrefUp = "EUP_"
+const elasticPrefLo = "ELO_"
+
+// elasticCol relates a violation column of the elastic model to its row.
+type elasticCol struct {
+	Row     int   // index of the row in the model
+	Col     int   // index of the violation column in the elastic model
+	IsUpper bool  // column measures violation of the upper limit
+}
+
+// elasticViol describes a row which had to be relaxed.
+type elasticViol struct {
+	Name     string   // row name
+	Type     string   // row type
+	Limit    float64  // limit violated
+	Activity float64  // left-hand side in the elastic solution
+	Amount   float64  // amount by which the limit is violated
+	IsUpper  bool     // upper limit is violated
+}
+
+//==============================================================================
+
+// selectRows returns the constraint rows of the model chosen by the selector,
+// which is "all", a row type (E, L or G), or a pattern matched against the row
+// names as described for path.Match. In case of failure, function returns an
+// error.
+func selectRows(model lpModel, selector string) ([]int, error) {
+	var rows []int  // rows selected
+
+	byType := strings.ToUpper(selector)
+	if byType != "E" && byType != "L" && byType != "G" {
+		byType = ""
+	}
+	if _, err := path.Match(selector, ""); err != nil {
+		return nil, errors.Errorf("Invalid row pattern '%s'", selector)
+	}
+
+	for i, row := range model.Rows {
+		if isFreeRow(row) {
+			continue
+		}
+		switch {
+		case selector == "all":
+		case byType != "":
+			if row.Type != byType {
+				continue
+			}
+		default:
+			if ok, _ := path.Match(selector, row.Name); !ok {
+				continue
+			}
+		}
+		rows = append(rows, i)
+	}
+
+	return rows, nil
+}
+
+//==============================================================================
+
+// elasticModel returns a copy of the model in which every selected row with a
+// finite upper limit gets a nonnegative column with coefficient -1, and every
+// selected row with a finite lower limit a column with coefficient +1, so that
+// the row can always be satisfied. The violation columns are given the penalty
+// weight in the objective, which keeps the original coefficients, scaled to a
+// minimization, only if keepObj is set. The function also returns the
+// violation columns added.
+func elasticModel(model lpModel, rows []int, weight float64, keepObj bool,
+	sense string) (lpModel, []elasticCol) {
+	var cols  []elasticCol              // violation columns added
+	var names = make(map[string]bool)  // column names in use
+
+	// Violation columns are continuous, and are given the type lpo uses for the
+	// continuous columns of the model.
+	contType := contColType(model)
+	elastic  := copyModel(model)
+	for _, col := range elastic.Cols {
+		names[col.Name] = true
+	}
+
+	objRow := elastic.ObjRow
+	for _, iElem := range elastic.Rows[objRow].HasElems {
+		switch {
+		case !keepObj:
+			elastic.Elems[iElem].Value = 0
+		case sense == senseMax:
+			elastic.Elems[iElem].Value = -elastic.Elems[iElem].Value
+		}
+	}
+
+	addElem := func(iRow, iCol int, value float64) {
+		elastic.Rows[iRow].HasElems = append(elastic.Rows[iRow].HasElems, len(elastic.Elems))
+		elastic.Cols[iCol].HasElems = append(elastic.Cols[iCol].HasElems, len(elastic.Elems))
+		elastic.Elems = append(elastic.Elems, lpo.InputElem{InRow: iRow, InCol: iCol, Value: value})
+	}
+
+	addCol := func(iRow int, isUpper bool) {
+		prefix, coef := elasticPrefLo, 1.0
+		if isUpper {
+			prefix, coef = elasticPrefUp, -1.0
+		}
+		name := prefix + elastic.Rows[iRow].Name
+		for k := 2; names[name]; k++ {
+			name = prefix + elastic.Rows[iRow].Name + "_" + strconv.Itoa(k)
+		}
+		names[name] = true
+
+		iCol := len(elastic.Cols)
+		elastic.Cols = append(elastic.Cols, lpo.InputCol{Name: name, Type: contType, BndLo: 0,
+			BndUp: lpo.Plinfy})
+		addElem(iRow, iCol, coef)
+		addElem(objRow, iCol, weight)
+		cols = append(cols, elasticCol{Row: iRow, Col: iCol, IsUpper: isUpper})
+	}
+
+	for _, iRow := range rows {
+		if !isPlinfy(model.Rows[iRow].RHSup) {
+			addCol(iRow, true)
+		}
+		if !isNeginf(model.Rows[iRow].RHSlo) {
+			addCol(iRow, false)
+		}
+	}
+
+	return elastic, cols
+}
+
+//==============================================================================
+
+// wpElastic builds an elastic copy of the model held in the lpo data structures
+// for the rows selected, solves it, and reports the rows which had to be
+// relaxed and by how much. The data structures and the lpo solution are left
+// unchanged, but the elastic model may be written to an MPS file.
+// In case of failure, function returns an error.
+func wpElastic() error {
+	var selector   string         // rows to be made elastic
+	var userString string         // input provided by user
+	var fileName   string         // MPS output file for the elastic model
+	var weight     float64        // penalty weight of a unit of violation
+	var keepObj    bool           // keep the original objective
+	var model      lpModel        // original model
+	var soln       lpo.PsSoln     // solution of the elastic model
+	var viols      []elasticViol  // rows relaxed
+	var total      float64        // total violation
+	var err        error          // error returned by functions called
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+		return errors.New("wpElastic failed, model not defined")
+	}
+	if lpo.ObjRow < 0 || lpo.ObjRow >= len(lpo.Rows) {
+		return errors.New("wpElastic failed, model has no objective row")
+	}
+	model = saveModel()
+
+	selector = promptArg(0, "Enter rows to relax ('all', row type E|L|G, or name pattern): ")
+	if selector == "" {
+		selector = "all"
+	}
+	rows, err := selectRows(model, selector)
+	if err != nil {
+		return errors.Wrap(err, "wpElastic failed")
+	}
+	if len(rows) == 0 {
+		return errors.Errorf("wpElastic failed, no rows match '%s'", selector)
+	}
+
+	weight = 1.0
+	fmt.Printf("Enter penalty weight or <CR> for %g: ", weight)
+	fmt.Scanln(&userString)
+	if userString != "" {
+		if weight, err = strconv.ParseFloat(userString, 64); err != nil || weight <= 0 {
+			return errors.Errorf("'%s' is not a valid weight.", userString)
+		}
+	}
+
+	userString = ""
+	fmt.Printf("Do you wish to keep the original objective as well [Y|N]: ")
+	fmt.Scanln(&userString)
+	keepObj = userString == "y" || userString == "Y"
+
+	useCoin := wpGetUseCoin()
+
+	elastic, cols := elasticModel(model, rows, weight, keepObj, objSense)
+	fmt.Printf("Elastic model has %d violation columns on %d rows.\n", len(cols), len(rows))
+
+	// The elastic model is always minimized, and the original one is restored
+	// whatever the outcome of the solve.
+	sense := objSense
+	defer func() {
+		objSense = sense
+		restoreModel(model)
+	}()
+
+	restoreModel(elastic)
+	objSense = senseMin
+	psCtrl  := lpo.PsCtrl{RunSolver: true, MaxIter: paramMaxIter(10)}
+	if err = solveWithCancel(0, useCoin, psCtrl, &soln); err != nil {
+		return errors.Wrap(err, "wpElastic failed")
+	}
+
+	x := make([]float64, len(elastic.Cols))
+	for j, col := range elastic.Cols {
+		x[j] = soln.VarMap[col.Name].Value
+	}
+
+	for _, ec := range cols {
+		if x[ec.Col] <= verifyTol {
+			continue
+		}
+		row := model.Rows[ec.Row]
+		v   := elasticViol{Name: row.Name, Type: row.Type, Limit: row.RHSlo,
+			Activity: rowActivity(model, ec.Row, x), Amount: x[ec.Col], IsUpper: ec.IsUpper}
+		if ec.IsUpper {
+			v.Limit = row.RHSup
+		}
+		viols  = append(viols, v)
+		total += v.Amount
+	}
+	sort.Slice(viols, func(a, b int) bool { return viols[a].Amount > viols[b].Amount })
+
+	origObj := 0.0
+	for j, coef := range objCoefs(model) {
+		origObj += coef * x[j]
+	}
+
+	fmt.Printf("\nElastic objective = %f, total violation = %e, original objective = %f.\n",
+		soln.ObjVal, total, origObj)
+
+	if len(viols) == 0 {
+		fmt.Printf("No row had to be relaxed, the model is feasible.\n")
+	} else {
+		fmt.Printf("%d rows had to be relaxed:\n\n", len(viols))
+		fmt.Printf("%-16s %2s %-5s %15s %15s %15s\n", "ROW", "EQ", "LIMIT", "VALUE",
+			"ACTIVITY", "VIOLATION")
+		for _, v := range viols {
+			side := "lower"
+			if v.IsUpper {
+				side = "upper"
+			}
+			fmt.Printf("%-16s %2s %-5s %15e %15e %15e\n", v.Name, v.Type, side, v.Limit,
+				v.Activity, math.Abs(v.Amount))
+		}
+	}
+
+	fmt.Printf("\nEnter MPS file name for the elastic model or <CR> for none: ")
+	fmt.Scanln(&fileName)
+	if fileName != "" {
+		if custEnvOn {
+			fileName = dSrcDev + "els_" + fileName + fExtension
+		}
+		restoreModel(elastic)
+		if err = lpo.WriteMpsFile(fileName); err != nil {
+			return errors.Wrap(err, "wpElastic failed")
+		}
+		fmt.Printf("Elastic model written to '%s'.\n", fileName)
+	}
+
+	return nil
+}
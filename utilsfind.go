@@ -0,0 +1,227 @@
+// This file contains the lookup of rows and columns of the model held in the
+// lpo data structures by name as well as by index, and the search of the model
+// for rows and columns meeting a selection.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// nameIndex maps the names of the rows and columns held in the lpo data
+// structures to their indexes. A name used more than once maps to its first
+// row or column.
+type nameIndex struct {
+	Rows map[string]int  // index of each row name
+	Cols map[string]int  // index of each column name
+}
+
+// Name index of the model held in the lpo data structures. It is rebuilt
+// whenever a lookup finds it out of date, so it needs no resetting when the
+// model changes.
+
+var lpoNames nameIndex
+
+//==============================================================================
+
+// rebuildNames builds the name index from the rows and columns held in the lpo
+// data structures. The function returns no values.
+func rebuildNames() {
+
+	lpoNames.Rows = make(map[string]int, len(lpo.Rows))
+	lpoNames.Cols = make(map[string]int, len(lpo.Cols))
+
+	for i := len(lpo.Rows) - 1; i >= 0; i-- {
+		lpoNames.Rows[lpo.Rows[i].Name] = i
+	}
+	for j := len(lpo.Cols) - 1; j >= 0; j-- {
+		lpoNames.Cols[lpo.Cols[j].Name] = j
+	}
+}
+
+//==============================================================================
+
+// lookupIndex returns the index of the row or column given by the argument,
+// which is either its index or its name. A name made up of digits must be
+// preceded by '=' to be taken as a name. The index map is checked against the
+// names given by nameAt, and rebuilt if it is out of date.
+// If there is no such row or column, function returns an error.
+func lookupIndex(arg, kind string, count int, names func() map[string]int,
+	nameAt func(int) string) (int, error) {
+
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		return -1, errors.Errorf("No %s given.", kind)
+	}
+
+	name := arg
+	if strings.HasPrefix(arg, "=") {
+		name = arg[1:]
+	} else if index, err := strconv.Atoi(arg); err == nil {
+		if index < 0 || index >= count {
+			return -1, errors.Errorf("%s index %d out of range.", strings.ToUpper(kind[:1]) + kind[1:], index)
+		}
+		return index, nil
+	}
+
+	index, ok := names()[name]
+	if !ok || index >= count || nameAt(index) != name {
+		rebuildNames()
+		if index, ok = names()[name]; !ok {
+			return -1, errors.Errorf("No %s named '%s'.", kind, name)
+		}
+	}
+
+	return index, nil
+}
+
+//==============================================================================
+
+// lookupRow returns the index of the row held in the lpo data structures given
+// by its index or name. If there is no such row, function returns an error.
+func lookupRow(arg string) (int, error) {
+
+	return lookupIndex(arg, "row", len(lpo.Rows), func() map[string]int { return lpoNames.Rows },
+		func(i int) string { return lpo.Rows[i].Name })
+}
+
+//==============================================================================
+
+// lookupCol returns the index of the column held in the lpo data structures
+// given by its index or name. If there is no such column, function returns an
+// error.
+func lookupCol(arg string) (int, error) {
+
+	return lookupIndex(arg, "column", len(lpo.Cols), func() map[string]int { return lpoNames.Cols },
+		func(j int) string { return lpo.Cols[j].Name })
+}
+
+//==============================================================================
+
+// printFound prints the rows, in equation format, or the columns, with their
+// type, bounds and number of elements, whose indexes are given, pausing every
+// pauseAfter items. The function returns no values.
+func printFound(indexes []int, isRow bool) {
+	var userString string  // input provided by user
+	var counter    int     // items printed since the last pause
+
+	for _, index := range indexes {
+		if isRow {
+			fmt.Printf("[%d] ", index)
+			if err := lpo.PrintRow(index); err != nil {
+				fmt.Println(err)
+			}
+		} else {
+			col := lpo.Cols[index]
+			colType := col.Type
+			if colType == "" {
+				colType = "C"
+			}
+			fmt.Printf("[%d] %-16s %s  [%g, %g]  %d elements\n", index, col.Name, colType,
+				col.BndLo, col.BndUp, len(col.HasElems))
+		}
+
+		counter++
+		if counter == pauseAfter {
+			counter    = 0
+			userString = ""
+			fmt.Printf("\nPAUSED... <CR> continue, any key to quit: ")
+			fmt.Scanln(&userString)
+			if userString != "" {
+				break
+			}
+		}
+	}
+
+}
+
+//==============================================================================
+
+// wpFind searches the model held in the lpo data structures, as given on the
+// command line or entered at the prompt:
+//   find var name|index   rows in which the column occurs
+//   find rows selection   rows meeting the selection
+//   find cols selection   columns meeting the selection
+// The selection is as for bulk deletion; a single word which is not one of its
+// conditions is taken as a name pattern. Rows found are printed in equation
+// format. In case of failure, function returns an error.
+func wpFind() error {
+	var found  []int     // indexes of the rows or columns found
+	var isRow  bool      // rows are searched rather than columns
+	var fields []string  // selection
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 {
+		return errors.New("wpFind failed, model not defined")
+	}
+
+	what := strings.ToLower(promptArg(0, "Find var, rows or cols: "))
+	switch what {
+	case "var", "col":
+		what = "var"
+	case "rows", "row":
+		isRow, what = true, "rows"
+	case "cols", "columns":
+		what = "columns"
+	default:
+		return errors.Errorf("wpFind failed, expected var, rows or cols, not '%s'", what)
+	}
+
+	if len(cmdArgs) > 1 {
+		fields = cmdArgs[1:]
+	} else if what == "var" {
+		fmt.Printf("Enter column name or index: ")
+		fields = strings.Fields(readLine())
+	} else {
+		fmt.Printf("Enter selection (name|regex|type|empty|singleton|fixed|free|lo|up|rhs ...): ")
+		fields = strings.Fields(readLine())
+	}
+
+	if what == "var" {
+		if len(fields) != 1 {
+			return errors.New("wpFind failed, give one column name or index")
+		}
+		iCol, err := lookupCol(fields[0])
+		if err != nil {
+			return errors.Wrap(err, "wpFind failed")
+		}
+		seen := make(map[int]bool)
+		for _, iElem := range lpo.Cols[iCol].HasElems {
+			if iRow := lpo.Elems[iElem].InRow; !seen[iRow] {
+				seen[iRow] = true
+				found = append(found, iRow)
+			}
+		}
+		fmt.Printf("Column %s occurs in %d rows:\n", lpo.Cols[iCol].Name, len(found))
+		printFound(found, true)
+		return nil
+	}
+
+	if len(fields) == 1 {
+		switch strings.ToLower(fields[0]) {
+		case "empty", "singleton", "fixed", "free":
+		default:
+			fields = []string{"name", fields[0]}
+		}
+	}
+	tests, err := bulkTests(fields, isRow)
+	if err != nil {
+		return errors.Wrap(err, "wpFind failed")
+	}
+
+	model := saveModel()
+	for index, isSelected := range bulkSelect(model, isRow, tests) {
+		if isSelected {
+			found = append(found, index)
+		}
+	}
+
+	fmt.Printf("%d %s found:\n", len(found), what)
+	printFound(found, isRow)
+
+	return nil
+}
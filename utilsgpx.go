@@ -30,6 +30,8 @@ var gObj    []gpx.InputObjCoef  // gpx input objective function coefficients
 var sObjVal   float64           // Solution value of objective function
 var sRows   []gpx.SolnRow       // Solution rows provided by gpx
 var sCols   []gpx.SolnCol       // Solution columns provided by gpx
+var cplexOpen bool              // Cplex problem created and not yet closed
+var cplexParamVersion int       // version of solver parameters when problem created
 
 
 //==============================================================================
@@ -52,6 +54,39 @@ func wpInitGpx() {
 
 //==============================================================================
 
+// wpCloseCplex closes the Cplex environment if a problem was created by one of
+// the gpx exerciser commands and not closed afterwards. It accepts no input and
+// returns no values.
+func wpCloseCplex() {
+
+	if !cplexOpen {
+		return
+	}
+
+	fmt.Printf("\nClosing Cplex.\n")
+	if err := gpx.CloseCplex(); err != nil {
+		fmt.Println(err)
+	}
+	cplexOpen = false
+
+}
+
+//==============================================================================
+
+// wpCheckCplexParams warns the user if solver parameters were changed after the
+// Cplex problem was created, since Cplex only reads them when its environment is
+// opened. It accepts no input and returns no values.
+func wpCheckCplexParams() {
+
+	if cplexOpen && cplexParamVersion != paramVersion {
+		fmt.Printf("WARNING: Solver parameters changed since the problem was created.\n")
+		fmt.Printf("Close and create the problem again for them to take effect.\n")
+	}
+
+}
+
+//==============================================================================
+
 // wpWriteGpx takes the model contained in the lpo structures, translates them to
 // the gpx data structures, and prints the contents of the gpx data structures in
 // a text file, which can be read at a later time by the gpxrun executable. The
@@ -458,6 +493,8 @@ func runGpxWrapper(cmdOption string) error {
 		if err = lpo.CplexCreateProb(); err != nil {
 			fmt.Println(err)
 		} else {
+			cplexOpen = true
+			cplexParamVersion = paramVersion
 			fmt.Printf("CplexCreateProb completed successfully.\n")
 		}
 
@@ -535,6 +572,7 @@ func runGpxWrapper(cmdOption string) error {
 		if err = gpx.CloseCplex(); err != nil {
 			fmt.Println(err)
 		} else {
+			cplexOpen = false
 			fmt.Printf("Cplex closed successfully.\n", userString)
 		}
 	
@@ -545,6 +583,8 @@ func runGpxWrapper(cmdOption string) error {
 		if err = gpx.CreateProb(userString); err != nil {
 			fmt.Println(err)
 		} else {
+			cplexOpen = true
+			cplexParamVersion = paramVersion
 			fmt.Printf("New problem with name '%s' created.\n", userString)
 		}
 
@@ -680,6 +720,7 @@ func runGpxWrapper(cmdOption string) error {
 	//--------------------------------------------------------------------------
 	case "75":
 		fmt.Printf("Optimizing existing LP.\n")
+		wpCheckCplexParams()
 		if err = gpx.LpOpt(); err != nil {
 			fmt.Println(err)
 		} else {
@@ -689,6 +730,7 @@ func runGpxWrapper(cmdOption string) error {
 	//--------------------------------------------------------------------------
 	case "76":
 		fmt.Printf("Optimizing existing MIP.\n")
+		wpCheckCplexParams()
 		if err = gpx.MipOpt(); err != nil {
 			fmt.Println(err)
 		} else {
@@ -766,3 +808,153 @@ func runGpxWrapper(cmdOption string) error {
 	return nil	
 }
 
+
+//==============================================================================
+
+// gpxSweep solves the points of a sweep with a single Cplex problem, so that
+// each solve is warm started from the basis of the previous one. The value
+// swept is held in one coefficient: for a right-hand side, that of an auxiliary
+// column fixed at 1 in the row, and for an objective coefficient, that of the
+// column in an auxiliary row defining a free column which replaces it in the
+// objective. Only that coefficient is changed between points. The problem is
+// closed once the sweep is over. In case of failure, function returns an error.
+func gpxSweep(model lpModel, spec sweepSpec) ([]sweepPoint, error) {
+	var rows      []gpx.InputRow      // rows of the Cplex problem
+	var cols      []gpx.InputCol      // columns of the Cplex problem
+	var elems     []gpx.InputElem     // elements of the Cplex problem
+	var obj       []gpx.InputObjCoef  // objective coefficients of the Cplex problem
+	var points    []sweepPoint        // results of the sweep
+	var sweepElem gpx.InputElem       // coefficient holding the value swept
+	var isMip     bool                // model has integer columns
+	var err       error               // error returned by functions called
+
+	if cplexOpen {
+		return nil, errors.New("gpxSweep failed, a Cplex problem is already open")
+	}
+
+	current := saveModel()
+	restoreModel(model)
+	err = lpo.TransToGpx(&rows, &cols, &elems, &obj)
+	restoreModel(current)
+	if err != nil {
+		return nil, errors.Wrap(err, "gpxSweep failed")
+	}
+
+	for _, col := range model.Cols {
+		if isIntCol(col) {
+			isMip = true
+		}
+	}
+
+	aux := len(cols)
+	if spec.IsRow {
+		cols = append(cols, gpx.InputCol{Name: sweepAuxName, Type: "C", BndLo: 1, BndUp: 1})
+		sweepElem = gpx.InputElem{RowIndex: -1, ColIndex: aux}
+		for i := range rows {
+			if rows[i].Name == spec.Name {
+				sweepElem.RowIndex = i
+			}
+		}
+	} else {
+		var kept []gpx.InputObjCoef  // objective coefficients of the other columns
+
+		sweepElem = gpx.InputElem{RowIndex: len(rows), ColIndex: -1}
+		for j := range cols {
+			if cols[j].Name == spec.Name {
+				sweepElem.ColIndex = j
+			}
+		}
+		for _, coef := range obj {
+			if coef.ColIndex != sweepElem.ColIndex {
+				kept = append(kept, coef)
+			}
+		}
+		obj   = append(kept, gpx.InputObjCoef{ColIndex: aux, Value: 1})
+		cols  = append(cols, gpx.InputCol{Name: sweepAuxName, Type: "C", BndLo: lpo.Neginf,
+			BndUp: lpo.Plinfy})
+		rows  = append(rows, gpx.InputRow{Name: sweepAuxName, Sense: "E", Rhs: 0})
+		elems = append(elems, gpx.InputElem{RowIndex: sweepElem.RowIndex, ColIndex: aux, Value: 1})
+	}
+	if sweepElem.RowIndex < 0 || sweepElem.ColIndex < 0 {
+		return nil, errors.Errorf("gpxSweep failed, '%s' not found in translated model", spec.Name)
+	}
+
+	// The coefficient is -delta for a right-hand side, and -t for an objective
+	// coefficient t, since the auxiliary row reads w - t x = 0.
+	setValue := func(value float64) {
+		sweepElem.Value = -value
+		if spec.IsRow {
+			sweepElem.Value = spec.Base - value
+		}
+	}
+	setValue(spec.Values[0])
+	elems = append(elems, sweepElem)
+
+	if err = gpx.CreateProb("sweep"); err != nil {
+		return nil, errors.Wrap(err, "gpxSweep failed")
+	}
+	cplexOpen = true
+	cplexParamVersion = paramVersion
+	defer wpCloseCplex()
+
+	if err = gpx.NewRows(rows); err != nil {
+		return nil, errors.Wrap(err, "gpxSweep failed")
+	}
+	if err = gpx.NewCols(obj, cols); err != nil {
+		return nil, errors.Wrap(err, "gpxSweep failed")
+	}
+	if err = gpx.ChgCoefList(elems); err != nil {
+		return nil, errors.Wrap(err, "gpxSweep failed")
+	}
+	if objSense == senseMax {
+		if err = gpx.ChgObjSen(-1); err != nil {
+			return nil, errors.Wrap(err, "gpxSweep failed")
+		}
+	}
+
+	ctx, endSolve := beginSolve(0)
+	defer endSolve()
+
+	for i, value := range spec.Values {
+		var objVal float64        // objective function value
+		var solnRows []gpx.SolnRow  // solution rows
+		var solnCols []gpx.SolnCol  // solution columns
+
+		if ctx.Err() != nil {
+			return points, errInterrupted
+		}
+
+		if i > 0 {
+			setValue(value)
+			if err = gpx.ChgCoefList([]gpx.InputElem{sweepElem}); err != nil {
+				return nil, errors.Wrap(err, "gpxSweep failed")
+			}
+		}
+
+		if isMip {
+			if err = gpx.MipOpt(); err == nil {
+				err = gpx.GetMipSolution(&objVal, &solnRows, &solnCols)
+			}
+		} else {
+			if err = gpx.LpOpt(); err == nil {
+				err = gpx.GetSolution(&objVal, &solnRows, &solnCols)
+			}
+		}
+		if err != nil {
+			points = append(points, sweepPoint{Value: value, Status: err.Error()})
+			continue
+		}
+
+		soln := lpo.PsSoln{ObjVal: objVal, VarMap: make(map[string]lpo.PsSolnVar),
+			ConMap: make(map[string]lpo.PsSolnCon)}
+		for _, col := range solnCols {
+			soln.VarMap[col.Name] = lpo.PsSolnVar{Value: col.Value}
+		}
+		for _, row := range solnRows {
+			soln.ConMap[row.Name] = lpo.PsSolnCon{Pi: row.Pi}
+		}
+		points = append(points, pointFromSoln(spec, value, soln))
+	}
+
+	return points, nil
+}
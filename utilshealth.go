@@ -0,0 +1,480 @@
+// This file contains the health report of the model held in the lpo data
+// structures: sizes, distributions of non-zeros and magnitudes, column kinds,
+// structural oddities, and warnings about the numerics.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Magnitude from which a coefficient, right-hand side or bound is reported as a
+// possible big-M value.
+
+const healthBigM = 1e6
+
+// Ratio of the largest to the smallest magnitude above which a range is
+// reported as badly conditioned.
+
+const healthMaxRatio = 1e9
+
+// Number of names given in a warning.
+
+const healthNames = 5
+
+// Upper limits of the bins of the non-zero count histograms; the last bin holds
+// the counts above the last limit.
+
+var healthBins = []int{0, 1, 2, 5, 10, 100, 1000}
+
+// healthRange holds the smallest and largest non-zero magnitude of a set of
+// values, and the ratio between them.
+type healthRange struct {
+	Count int      // non-zero values
+	Min   float64  // smallest magnitude
+	Max   float64  // largest magnitude
+	Ratio float64  // Max / Min, 1 if there are no values
+	NaN   int      // values which are not numbers, left out of the range
+}
+
+// healthBin holds the number of rows or columns with a number of non-zeros in
+// the range given by its label.
+type healthBin struct {
+	Label string  // range of non-zero counts, e.g. "3-5"
+	Count int     // rows or columns in the range
+}
+
+// healthReport holds the health report of a model.
+type healthReport struct {
+	Name          string          // model name
+	Rows          int             // rows, including the objective
+	Cols          int             // columns
+	Elems         int             // elements, including the objective
+	Sense         string          // objective sense
+	RowTypes      map[string]int  // constraint rows by type
+	RowNonzeros   []healthBin     // histogram of constraint row non-zeros
+	ColNonzeros   []healthBin     // histogram of column non-zeros outside the objective
+	Coefs         healthRange     // constraint coefficients
+	Rhs           healthRange     // finite row limits
+	Bounds        healthRange     // finite column bounds
+	Objective     healthRange     // objective coefficients
+	FreeCols      int             // columns with no finite bound
+	FixedCols     int             // columns with equal bounds
+	BoxedCols     int             // columns with two different finite bounds
+	LowerCols     int             // columns with a finite lower bound only
+	UpperCols     int             // columns with a finite upper bound only
+	IntCols       int             // general integer columns
+	BinCols       int             // binary columns
+	EmptyRows     int             // constraint rows without elements
+	EmptyCols     int             // columns without elements outside the objective
+	SingletonRows int             // constraint rows with one element
+	SingletonCols int             // columns with one element outside the objective
+	DupRowNames   []string        // row names used more than once
+	DupColNames   []string        // column names used more than once
+	Warnings      []string        // numerical and structural warnings
+}
+
+//==============================================================================
+
+// addMagnitude adds the magnitude of a value to the range, unless it is zero or
+// infinite. A value which is not a number is only counted, so that it cannot
+// spoil the range. The function returns no values.
+func addMagnitude(r *healthRange, value float64) {
+
+	if math.IsNaN(value) {
+		r.NaN++
+		return
+	}
+	if value == 0 || isPlinfy(value) || isNeginf(value) {
+		return
+	}
+
+	value = math.Abs(value)
+	if r.Count == 0 || value < r.Min {
+		r.Min = value
+	}
+	if r.Count == 0 || value > r.Max {
+		r.Max = value
+	}
+	r.Count++
+	r.Ratio = r.Max / r.Min
+}
+
+//==============================================================================
+
+// histogram returns the histogram of the counts given over healthBins.
+func histogram(counts []int) []healthBin {
+	var bins []healthBin  // histogram being built
+
+	low := 0
+	for _, limit := range healthBins {
+		label := fmt.Sprintf("%d-%d", low, limit)
+		if low == limit {
+			label = fmt.Sprintf("%d", limit)
+		}
+		bins = append(bins, healthBin{Label: label})
+		low = limit + 1
+	}
+	bins = append(bins, healthBin{Label: fmt.Sprintf("%d+", low)})
+
+	for _, count := range counts {
+		k := sort.SearchInts(healthBins, count)
+		bins[k].Count++
+	}
+
+	return bins
+}
+
+//==============================================================================
+
+// duplicateNames returns the names found more than once in the list, sorted.
+func duplicateNames(names []string) []string {
+	var dups []string                   // names found more than once
+	var seen = make(map[string]int)     // times each name was found
+
+	for _, name := range names {
+		seen[name]++
+		if seen[name] == 2 {
+			dups = append(dups, name)
+		}
+	}
+	sort.Strings(dups)
+
+	return dups
+}
+
+//==============================================================================
+
+// listNames returns the first healthNames names of the list joined for a
+// message, with the number of names left out.
+func listNames(names []string) string {
+
+	if len(names) <= healthNames {
+		return strings.Join(names, ", ")
+	}
+
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:healthNames], ", "),
+		len(names) - healthNames)
+}
+
+//==============================================================================
+
+// healthWarnings returns the warnings raised by the report and the model: big-M
+// coefficients, limits and bounds, ranges of magnitude wider than
+// healthMaxRatio over the model or within a row or column, empty rows and
+// columns, and duplicate names.
+func healthWarnings(model lpModel, report healthReport) []string {
+	var warnings []string  // warnings raised
+	var bigCoef  []string  // rows holding big-M coefficients
+	var bigRhs   []string  // rows with big-M limits
+	var bigBnd   []string  // columns with big-M bounds
+	var wideRow  []string  // rows with a badly conditioned range
+	var wideCol  []string  // columns with a badly conditioned range
+
+	isBig := func(value float64) bool {
+		return math.Abs(value) >= healthBigM && !isPlinfy(value) && !isNeginf(value)
+	}
+
+	for i, row := range model.Rows {
+		if !isScaledRow(model, i) {
+			continue
+		}
+		for _, iElem := range row.HasElems {
+			if isBig(model.Elems[iElem].Value) {
+				bigCoef = append(bigCoef, row.Name)
+				break
+			}
+		}
+		if isBig(row.RHSlo) || isBig(row.RHSup) {
+			bigRhs = append(bigRhs, row.Name)
+		}
+	}
+	for _, col := range model.Cols {
+		if isBig(col.BndLo) || isBig(col.BndUp) {
+			bigBnd = append(bigBnd, col.Name)
+		}
+	}
+
+	rows, cols := coefRanges(model, unitFactors(len(model.Rows)), unitFactors(len(model.Cols)))
+	for _, r := range rows {
+		if rangeRatio(r) > healthMaxRatio {
+			wideRow = append(wideRow, r.Name)
+		}
+	}
+	for _, r := range cols {
+		if rangeRatio(r) > healthMaxRatio {
+			wideCol = append(wideCol, r.Name)
+		}
+	}
+
+	if len(bigCoef) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d rows hold coefficients of magnitude %g or more, possible big-M: %s",
+			len(bigCoef), healthBigM, listNames(bigCoef)))
+	}
+	if len(bigRhs) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d rows have limits of magnitude %g or more, possible big-M: %s",
+			len(bigRhs), healthBigM, listNames(bigRhs)))
+	}
+	if len(bigBnd) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d columns have bounds of magnitude %g or more, possibly standing for infinity: %s",
+			len(bigBnd), healthBigM, listNames(bigBnd)))
+	}
+
+	for _, r := range []struct {
+		what string
+		rng  healthRange
+	}{{"Coefficient", report.Coefs}, {"Right-hand side", report.Rhs},
+		{"Bound", report.Bounds}, {"Objective", report.Objective}} {
+		if r.rng.NaN > 0 {
+			warnings = append(warnings, fmt.Sprintf("%s values: %d are not numbers (NaN)", r.what,
+				r.rng.NaN))
+		}
+		if r.rng.Ratio > healthMaxRatio {
+			warnings = append(warnings, fmt.Sprintf("%s magnitudes range over %.3g (%g to %g), above %g",
+				r.what, r.rng.Ratio, r.rng.Min, r.rng.Max, healthMaxRatio))
+		}
+	}
+	if len(wideRow) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d rows have coefficients ranging over more than %g: %s",
+			len(wideRow), healthMaxRatio, listNames(wideRow)))
+	}
+	if len(wideCol) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d columns have coefficients ranging over more than %g: %s",
+			len(wideCol), healthMaxRatio, listNames(wideCol)))
+	}
+
+	if report.EmptyRows > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d constraint rows are empty", report.EmptyRows))
+	}
+	if report.EmptyCols > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d columns appear in no constraint", report.EmptyCols))
+	}
+	if len(report.DupRowNames) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d row names are used more than once: %s",
+			len(report.DupRowNames), listNames(report.DupRowNames)))
+	}
+	if len(report.DupColNames) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d column names are used more than once: %s",
+			len(report.DupColNames), listNames(report.DupColNames)))
+	}
+
+	return warnings
+}
+
+//==============================================================================
+
+// healthCheck returns the health report of the model. The objective and other
+// free rows are counted apart from the constraint rows.
+func healthCheck(model lpModel) healthReport {
+	var rowCounts []int     // non-zeros of each constraint row
+	var colCounts []int     // non-zeros of each column outside the objective
+	var rowNames  []string  // names of all rows
+	var colNames  []string  // names of all columns
+
+	report := healthReport{Name: model.Name, Rows: len(model.Rows), Cols: len(model.Cols),
+		Elems: len(model.Elems), Sense: objSense, RowTypes: make(map[string]int)}
+
+	for i, row := range model.Rows {
+		rowNames = append(rowNames, row.Name)
+		if !isScaledRow(model, i) {
+			continue
+		}
+		report.RowTypes[row.Type]++
+
+		count := 0
+		for _, iElem := range row.HasElems {
+			if model.Elems[iElem].Value != 0 {
+				count++
+			}
+			addMagnitude(&report.Coefs, model.Elems[iElem].Value)
+		}
+		rowCounts = append(rowCounts, count)
+		switch count {
+		case 0:
+			report.EmptyRows++
+		case 1:
+			report.SingletonRows++
+		}
+
+		addMagnitude(&report.Rhs, row.RHSlo)
+		if row.RHSup != row.RHSlo {
+			addMagnitude(&report.Rhs, row.RHSup)
+		}
+	}
+
+	for _, coef := range objCoefs(model) {
+		addMagnitude(&report.Objective, coef)
+	}
+
+	for j, col := range model.Cols {
+		colNames = append(colNames, col.Name)
+
+		count := constraintElems(model, j)
+		colCounts = append(colCounts, count)
+		switch count {
+		case 0:
+			report.EmptyCols++
+		case 1:
+			report.SingletonCols++
+		}
+
+		addMagnitude(&report.Bounds, col.BndLo)
+		if col.BndUp != col.BndLo {
+			addMagnitude(&report.Bounds, col.BndUp)
+		}
+
+		hasLo, hasUp := !isNeginf(col.BndLo), !isPlinfy(col.BndUp)
+		switch {
+		case hasLo && hasUp && col.BndLo == col.BndUp:
+			report.FixedCols++
+		case hasLo && hasUp:
+			report.BoxedCols++
+		case hasLo:
+			report.LowerCols++
+		case hasUp:
+			report.UpperCols++
+		default:
+			report.FreeCols++
+		}
+
+		switch {
+		case col.Type == "B" || (col.Type == "I" && col.BndLo == 0 && col.BndUp == 1):
+			report.BinCols++
+		case col.Type == "I":
+			report.IntCols++
+		}
+	}
+
+	for _, r := range []*healthRange{&report.Coefs, &report.Rhs, &report.Bounds, &report.Objective} {
+		if r.Count == 0 {
+			r.Ratio = 1
+		}
+	}
+
+	report.RowNonzeros = histogram(rowCounts)
+	report.ColNonzeros = histogram(colCounts)
+	report.DupRowNames = duplicateNames(rowNames)
+	report.DupColNames = duplicateNames(colNames)
+	report.Warnings    = healthWarnings(model, report)
+
+	return report
+}
+
+//==============================================================================
+
+// writeHealthText writes the health report as text. The function returns no
+// values.
+func writeHealthText(w io.Writer, report healthReport) {
+	var types []string  // row types present
+
+	fmt.Fprintf(w, "\nHEALTH REPORT FOR MODEL '%s' (%s)\n", report.Name, report.Sense)
+	fmt.Fprintf(w, "\n%d rows, %d columns, %d elements\n", report.Rows, report.Cols, report.Elems)
+
+	for rowType := range report.RowTypes {
+		types = append(types, rowType)
+	}
+	sort.Strings(types)
+	fmt.Fprintf(w, "Constraint rows by type:")
+	for _, rowType := range types {
+		fmt.Fprintf(w, "  %s %d", rowType, report.RowTypes[rowType])
+	}
+	fmt.Fprintf(w, "\n")
+
+	fmt.Fprintf(w, "\n%-12s %12s %12s\n", "Non-zeros", "Rows", "Columns")
+	for k := range report.RowNonzeros {
+		fmt.Fprintf(w, "%-12s %12d %12d\n", report.RowNonzeros[k].Label,
+			report.RowNonzeros[k].Count, report.ColNonzeros[k].Count)
+	}
+
+	fmt.Fprintf(w, "\n%-16s %10s %12s %12s %12s\n", "Magnitudes", "Count", "Min", "Max", "Ratio")
+	for _, r := range []struct {
+		what string
+		rng  healthRange
+	}{{"Coefficients", report.Coefs}, {"Right-hand sides", report.Rhs},
+		{"Bounds", report.Bounds}, {"Objective", report.Objective}} {
+		fmt.Fprintf(w, "%-16s %10d %12.4g %12.4g %12.4g\n", r.what, r.rng.Count, r.rng.Min,
+			r.rng.Max, r.rng.Ratio)
+	}
+
+	fmt.Fprintf(w, "\nColumns: %d free, %d fixed, %d boxed, %d lower bound only, %d upper bound only\n",
+		report.FreeCols, report.FixedCols, report.BoxedCols, report.LowerCols, report.UpperCols)
+	fmt.Fprintf(w, "         %d general integer, %d binary\n", report.IntCols, report.BinCols)
+	fmt.Fprintf(w, "Empty:     %d rows, %d columns\n", report.EmptyRows, report.EmptyCols)
+	fmt.Fprintf(w, "Singleton: %d rows, %d columns\n", report.SingletonRows, report.SingletonCols)
+	fmt.Fprintf(w, "Duplicate names: %d rows, %d columns\n", len(report.DupRowNames),
+		len(report.DupColNames))
+
+	if len(report.Warnings) == 0 {
+		fmt.Fprintf(w, "\nNo warnings.\n")
+		return
+	}
+	fmt.Fprintf(w, "\n%d WARNINGS:\n", len(report.Warnings))
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(w, "  - %s\n", warning)
+	}
+
+}
+
+//==============================================================================
+
+// wpHealth prints the health report of the model held in the lpo data
+// structures, or writes it to a file, as text or JSON. The format and file are
+// given on the command line as "health [text|json] [file]", text to the screen
+// being the default. In case of failure, function returns an error.
+func wpHealth() error {
+	var out      io.Writer = os.Stdout  // destination of the report
+	var fileName string                 // output file, empty for the screen
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+		return errors.New("wpHealth failed, model not defined")
+	}
+
+	format := "text"
+	if len(cmdArgs) > 0 {
+		format = strings.ToLower(cmdArgs[0])
+	}
+	if format != "text" && format != "json" {
+		return errors.Errorf("wpHealth failed, unknown format '%s'", format)
+	}
+
+	if len(cmdArgs) > 1 {
+		fileName = cmdArgs[1]
+		if custEnvOn {
+			fileName = dSrcDev + fileName + "." + format
+		}
+		f, err := os.Create(fileName)
+		if err != nil {
+			return errors.Wrapf(err, "wpHealth failed to create file %s", fileName)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	report := healthCheck(saveModel())
+	if format == "text" {
+		writeHealthText(out, report)
+	} else {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "wpHealth failed to encode the report")
+		}
+		if _, err = fmt.Fprintf(out, "%s\n", data); err != nil {
+			return errors.Wrap(err, "wpHealth failed to write the report")
+		}
+	}
+
+	if fileName != "" {
+		fmt.Printf("Health report written to '%s'.\n", fileName)
+	}
+
+	return nil
+}
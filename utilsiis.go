@@ -0,0 +1,346 @@
+// This file contains the search for an irreducible infeasible subsystem (IIS)
+// of the model held in the lpo data structures.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Texts which, found in the error of a failed solve, report the model as
+// infeasible, matched in lower case. They are taken from the solution status of
+// Cplex ("Infeasible", "Integer infeasible", as in the Cplex solution status
+// strings "infeasible" and "integer infeasible"), and of the Coin-OR solvers
+// ("Primal infeasible" from Clp, "Problem is infeasible" and "No feasible
+// solution found" from Cbc). The wording lpo passes on from each solver has not
+// been checked for every version, so a solve failing with any other message is
+// reported, not counted as infeasible.
+
+var infeasibleTexts = []string{"infeasible", "no feasible solution"}
+
+// iisMember is a row or a column bound which may belong to an IIS.
+type iisMember struct {
+	IsRow   bool  // member is a row rather than a column bound
+	Index   int   // index of the row or column in the model
+	IsUpper bool  // for a column, member is its upper bound rather than its lower one
+}
+
+//==============================================================================
+
+// iisMembers returns every constraint row of the model, and every finite bound
+// of its columns, as candidate members of an IIS.
+func iisMembers(model lpModel) []iisMember {
+	var members []iisMember  // candidates found
+
+	for i, row := range model.Rows {
+		if !isFreeRow(row) {
+			members = append(members, iisMember{IsRow: true, Index: i})
+		}
+	}
+
+	for j, col := range model.Cols {
+		if !isNeginf(col.BndLo) {
+			members = append(members, iisMember{Index: j})
+		}
+		if !isPlinfy(col.BndUp) {
+			members = append(members, iisMember{Index: j, IsUpper: true})
+		}
+	}
+
+	return members
+}
+
+//==============================================================================
+
+// relaxedModel returns a copy of the model with a zero objective, so that only
+// feasibility matters, in which the members marked as relaxed are removed: a
+// relaxed row is given infinite limits, and a relaxed bound is made infinite.
+func relaxedModel(model lpModel, members []iisMember, relaxed []bool) lpModel {
+
+	work := copyModel(model)
+
+	if work.ObjRow >= 0 && work.ObjRow < len(work.Rows) {
+		for _, iElem := range work.Rows[work.ObjRow].HasElems {
+			work.Elems[iElem].Value = 0
+		}
+	}
+
+	for i, member := range members {
+		if !relaxed[i] {
+			continue
+		}
+		switch {
+		case member.IsRow:
+			work.Rows[member.Index].Type  = "L"
+			work.Rows[member.Index].RHSlo = lpo.Neginf
+			work.Rows[member.Index].RHSup = lpo.Plinfy
+		case member.IsUpper:
+			work.Cols[member.Index].BndUp = lpo.Plinfy
+		default:
+			work.Cols[member.Index].BndLo = lpo.Neginf
+		}
+	}
+
+	return work
+}
+
+//==============================================================================
+
+// isInfeasibleErr determines whether the error returned by a solve reports the
+// model as infeasible. lpo gives no status for a failed solve, only the message
+// of the solver, so the message is checked for infeasibleTexts. A change in the
+// wording of a solver makes its infeasible solves count as failures, which stop
+// the IIS search with the message received.
+func isInfeasibleErr(err error) bool {
+
+	message := strings.ToLower(err.Error())
+	for _, text := range infeasibleTexts {
+		if strings.Contains(message, text) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//==============================================================================
+
+// isInfeasible solves the model with the solver selected and no reductions, and
+// determines whether it is infeasible, which is the case only if the solver
+// reports the model as infeasible. If the solve fails for any other reason, or
+// is interrupted, function returns an error.
+func isInfeasible(ctx context.Context, model lpModel, useCoin bool) (bool, error) {
+	var soln lpo.PsSoln  // solution, not used
+
+	restoreModel(model)
+	psCtrl := lpo.PsCtrl{RunSolver: true, MaxIter: paramMaxIter(10)}
+
+	err := solveInContext(ctx, useCoin, psCtrl, &soln)
+	switch {
+	case err == nil:
+		return false, nil
+	case err == errInterrupted || err == errTimeLimit:
+		return false, err
+	case isInfeasibleErr(err):
+		return true, nil
+	}
+
+	return false, errors.Errorf("solve failed with a message not recognised as infeasible: %q",
+		firstLine(err.Error()))
+}
+
+//==============================================================================
+
+// findIIS finds an IIS of the model with a deletion filter. Blocks of members
+// are relaxed together, starting with blocks of half the candidates and halving
+// the size down to single members; a block is dropped if the model stays
+// infeasible without it. The final pass over single members makes the set
+// found irreducible. The function returns the members of the IIS and the number
+// of solves made. In case of failure, function returns an error.
+func findIIS(ctx context.Context, model lpModel, useCoin bool) ([]iisMember, int, error) {
+	var iis    []iisMember  // members of the IIS
+	var solves int          // number of solves made
+
+	members := iisMembers(model)
+	relaxed := make([]bool, len(members))
+
+	infeasible, err := isInfeasible(ctx, relaxedModel(model, members, relaxed), useCoin)
+	solves++
+	if err != nil {
+		return nil, solves, err
+	}
+	if !infeasible {
+		return nil, solves, errors.New("Model is feasible, it has no IIS")
+	}
+
+	for size := (len(members) + 1) / 2; size >= 1; size = (size + 1) / 2 {
+		var kept []int  // candidates still in the IIS
+
+		for i := range members {
+			if !relaxed[i] {
+				kept = append(kept, i)
+			}
+		}
+		fmt.Printf("Testing %d candidates in blocks of %d.\n", len(kept), size)
+
+		for start := 0; start < len(kept); start += size {
+			end := start + size
+			if end > len(kept) {
+				end = len(kept)
+			}
+
+			for _, i := range kept[start:end] {
+				relaxed[i] = true
+			}
+			infeasible, err = isInfeasible(ctx, relaxedModel(model, members, relaxed), useCoin)
+			solves++
+			if err != nil {
+				return nil, solves, err
+			}
+			if !infeasible {
+				// The block holds part of the IIS, so it is restored.
+				for _, i := range kept[start:end] {
+					relaxed[i] = false
+				}
+			}
+		}
+
+		if size == 1 {
+			break
+		}
+	}
+
+	for i, member := range members {
+		if !relaxed[i] {
+			iis = append(iis, member)
+		}
+	}
+
+	return iis, solves, nil
+}
+
+//==============================================================================
+
+// printIIS prints the rows of the IIS as lpo.PrintRow does, followed by the
+// column bounds. The model must be held in the lpo data structures. The
+// function returns no values.
+func printIIS(model lpModel, iis []iisMember) {
+
+	fmt.Printf("\nIIS rows:\n")
+	for _, member := range iis {
+		if member.IsRow {
+			if err := lpo.PrintRow(member.Index); err != nil {
+				fmt.Println(err)
+			}
+		}
+	}
+
+	fmt.Printf("\nIIS bounds:\n")
+	for _, member := range iis {
+		if member.IsRow {
+			continue
+		}
+		col := model.Cols[member.Index]
+		if member.IsUpper {
+			fmt.Printf("  %s <= %g\n", col.Name, col.BndUp)
+		} else {
+			fmt.Printf("  %s >= %g\n", col.Name, col.BndLo)
+		}
+	}
+
+}
+
+//==============================================================================
+
+// iisModel returns the model made up of the rows of the IIS and the columns
+// appearing in them. Column bounds which are not part of the IIS are relaxed.
+func iisModel(model lpModel, iis []iisMember) lpModel {
+	var rows    []int                  // rows of the IIS
+	var cols    []int                  // columns appearing in the IIS
+	var colUsed = make(map[int]bool)   // column already listed
+	var lower   = make(map[int]bool)   // lower bound of column is in the IIS
+	var upper   = make(map[int]bool)   // upper bound of column is in the IIS
+
+	addCol := func(iCol int) {
+		if !colUsed[iCol] {
+			colUsed[iCol] = true
+			cols = append(cols, iCol)
+		}
+	}
+
+	for _, member := range iis {
+		switch {
+		case member.IsRow:
+			rows = append(rows, member.Index)
+			for _, iElem := range model.Rows[member.Index].HasElems {
+				addCol(model.Elems[iElem].InCol)
+			}
+		case member.IsUpper:
+			upper[member.Index] = true
+			addCol(member.Index)
+		default:
+			lower[member.Index] = true
+			addCol(member.Index)
+		}
+	}
+
+	sub := subModel(model, rows, cols)
+	for k, iCol := range cols {
+		if !lower[iCol] {
+			sub.Cols[k].BndLo = lpo.Neginf
+		}
+		if !upper[iCol] {
+			sub.Cols[k].BndUp = lpo.Plinfy
+		}
+	}
+	if sub.ObjRow >= 0 {
+		for _, iElem := range sub.Rows[sub.ObjRow].HasElems {
+			sub.Elems[iElem].Value = 0
+		}
+	}
+
+	return sub
+}
+
+//==============================================================================
+
+// wpFindIIS finds an irreducible infeasible subsystem of the model held in the
+// lpo data structures: a set of rows and column bounds which is infeasible, but
+// becomes feasible if any one of them is removed. The IIS is printed, and may be
+// written to an MPS file. The data structures hold the original model once the
+// search is over. In case of failure, function returns an error.
+func wpFindIIS() error {
+	var fileName string   // MPS output file for the IIS
+	var model    lpModel  // model searched
+	var numRows  int      // rows in the IIS
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+		return errors.New("wpFindIIS failed, model not defined")
+	}
+
+	model    = saveModel()
+	useCoin := wpGetUseCoin()
+	defer restoreModel(model)
+
+	ctx, endSolve := beginSolve(0)
+	defer endSolve()
+
+	fmt.Printf("Searching for IIS, press Ctrl-C to stop.\n")
+	iis, solves, err := findIIS(ctx, model, useCoin)
+	if err != nil {
+		return errors.Wrap(err, "wpFindIIS failed")
+	}
+
+	for _, member := range iis {
+		if member.IsRow {
+			numRows++
+		}
+	}
+	fmt.Printf("\nIIS found with %d solves: %d rows and %d bounds.\n", solves, numRows,
+		len(iis) - numRows)
+
+	restoreModel(model)
+	printIIS(model, iis)
+
+	fmt.Printf("\nEnter MPS file name for the IIS or <CR> for none: ")
+	fmt.Scanln(&fileName)
+	if fileName == "" {
+		return nil
+	}
+	if custEnvOn {
+		fileName = dSrcDev + "iis_" + fileName + fExtension
+	}
+
+	restoreModel(iisModel(model, iis))
+	if err = lpo.WriteMpsFile(fileName); err != nil {
+		return errors.Wrap(err, "wpFindIIS failed")
+	}
+	fmt.Printf("IIS written to '%s'.\n", fileName)
+
+	return nil
+}
@@ -21,7 +21,6 @@ import (
 // list. In case of failure, function returns an error.
 func wpGetPoint(rowIndex *int, point *[]float64) error {
 	var userString string   // user input string
-	var bigInt     int64    // big int translated from the user input
 	var pointItem  float64  // item of the point list as the point is created
 	var iElem      int      // index of element being processed
 	var iCol       int      // index of column being processed
@@ -32,18 +31,12 @@ func wpGetPoint(rowIndex *int, point *[]float64) error {
 	*rowIndex = -1
 	*point    = nil
 	
-	fmt.Printf("Enter constraint index: ")
+	fmt.Printf("Enter constraint index or name: ")
 	fmt.Scanln(&userString)
-	if bigInt, err = strconv.ParseInt(userString,10,64); err != nil {
-    	return errors.Errorf("'%s' is not an integer.", userString)
-	}
-
-	// Check that the input is valid, or fail with error if not. If valid, add
-	// the variable value to the list.	
-	*rowIndex = int(bigInt)
 
-	if *rowIndex < 0 || *rowIndex >= len(lpo.Rows) {
-		return errors.Errorf("Row index %d out of range.", *rowIndex)
+	// Check that the input is a valid index or name, or fail with error if not.
+	if *rowIndex, err = lookupRow(userString); err != nil {
+		return err
 	}
 
 	fmt.Printf("Variable values are needed for the following constraint:\n")
@@ -148,7 +141,7 @@ func runMainWrapper(cmdOption string) error {
 				fileName = dSrcDev + fileName + fExtension
 			}
 			fmt.Println("Reading file", fileName)
-			if err = lpo.ReadMpsFile(fileName); err != nil {
+			if err = readMpsSense(fileName); err != nil {
 				fmt.Println(err)
 			}
 
@@ -159,7 +152,7 @@ func runMainWrapper(cmdOption string) error {
 			if custEnvOn {
 				fileName = dSrcDev + fileName + fExtension
 			}
-			err = lpo.WriteMpsFile(fileName)
+			err = writeMpsSense(fileName)
 			if err != nil {
 				fmt.Println(err)
 			} else {
@@ -308,22 +301,34 @@ func runLpoWrapper(cmdOption string) error {
 
 	//--------------------------------------------------------------------------
 	case "31":
-		fmt.Printf("Enter index of column to delete: ")
-		fmt.Scanln(&userInt)
-		if err = lpo.DelCol(userInt); err != nil {
+		userString = ""
+		fmt.Printf("Enter index or name of column to delete: ")
+		fmt.Scanln(&userString)
+		if userInt, err = lookupCol(userString); err != nil {
 			fmt.Println(err)
 		} else {
-			fmt.Printf("Column %d successfully deleted.\n", userInt)
+			tmpString = lpo.Cols[userInt].Name
+			if err = lpo.DelCol(userInt); err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Printf("Column %d (%s) successfully deleted.\n", userInt, tmpString)
+			}
 		}
 
 	//--------------------------------------------------------------------------
 	case "32":
-		fmt.Printf("Enter index of row to delete: ")
-		fmt.Scanln(&userInt)
-		if err = lpo.DelRow(userInt); err != nil {
+		userString = ""
+		fmt.Printf("Enter index or name of row to delete: ")
+		fmt.Scanln(&userString)
+		if userInt, err = lookupRow(userString); err != nil {
 			fmt.Println(err)
 		} else {
-			fmt.Printf("Row %d successfully deleted.\n", userInt)
+			tmpString = lpo.Rows[userInt].Name
+			if err = lpo.DelRow(userInt); err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Printf("Row %d (%s) successfully deleted.\n", userInt, tmpString)
+			}
 		}
 
 	//--------------------------------------------------------------------------
@@ -360,9 +365,12 @@ func runLpoWrapper(cmdOption string) error {
 
 	//--------------------------------------------------------------------------
 	case "37":
-		fmt.Printf("Enter index of column to print: ")
-		fmt.Scanln(&userInt)
-		if err = lpo.PrintCol(userInt); err != nil {
+		userString = ""
+		fmt.Printf("Enter index or name of column to print: ")
+		fmt.Scanln(&userString)
+		if userInt, err = lookupCol(userString); err != nil {
+			fmt.Println(err)
+		} else if err = lpo.PrintCol(userInt); err != nil {
 			fmt.Println(err)				
 		}
 
@@ -380,9 +388,12 @@ func runLpoWrapper(cmdOption string) error {
 
 	//--------------------------------------------------------------------------
 	case "40":
-		fmt.Printf("Enter index of row to print: ")
-		fmt.Scanln(&userInt)
-		if err = lpo.PrintRow(userInt); err != nil {
+		userString = ""
+		fmt.Printf("Enter index or name of row to print: ")
+		fmt.Scanln(&userString)
+		if userInt, err = lookupRow(userString); err != nil {
+			fmt.Println(err)
+		} else if err = lpo.PrintRow(userInt); err != nil {
 			fmt.Println(err)				
 		}
 
@@ -406,7 +417,7 @@ func runLpoWrapper(cmdOption string) error {
 			fileName = dSrcDev + fileName + fExtension
 		}
 		fmt.Println("Reading file", fileName)
-		if err = lpo.ReadMpsFile(fileName); err != nil {
+		if err = readMpsSense(fileName); err != nil {
 			fmt.Println(err)
 		}
 
@@ -468,7 +479,7 @@ func runLpoWrapper(cmdOption string) error {
 		if custEnvOn {
 			fileName = dSrcDev + fileName + fExtension
 		}
-		err = lpo.WriteMpsFile(fileName)
+		err = writeMpsSense(fileName)
 		if err != nil {
 			fmt.Println(err)
 		} else {
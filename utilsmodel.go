@@ -0,0 +1,317 @@
+// This file contains helpers for working with the model held in the lpo data
+// structures: saving and restoring copies of it, and interpreting its rows and
+// columns.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"math"
+)
+
+// lpModel is a copy of the model held in the lpo package global variables.
+type lpModel struct {
+	Name   string           // problem name
+	ObjRow int              // index of objective function row
+	Rows   []lpo.InputRow   // rows, including the objective function
+	Cols   []lpo.InputCol   // columns
+	Elems  []lpo.InputElem  // non-zero elements
+}
+
+//==============================================================================
+
+// copyModel returns a deep copy of the model provided, so that changes to one
+// do not affect the other.
+func copyModel(model lpModel) lpModel {
+	var dup lpModel  // copy being made
+
+	dup.Name   = model.Name
+	dup.ObjRow = model.ObjRow
+	dup.Rows   = make([]lpo.InputRow,  len(model.Rows))
+	dup.Cols   = make([]lpo.InputCol,  len(model.Cols))
+	dup.Elems  = make([]lpo.InputElem, len(model.Elems))
+
+	copy(dup.Rows,  model.Rows)
+	copy(dup.Cols,  model.Cols)
+	copy(dup.Elems, model.Elems)
+
+	for i := range dup.Rows {
+		dup.Rows[i].HasElems = append([]int(nil), model.Rows[i].HasElems...)
+	}
+	for i := range dup.Cols {
+		dup.Cols[i].HasElems = append([]int(nil), model.Cols[i].HasElems...)
+	}
+
+	return dup
+}
+
+//==============================================================================
+
+// saveModel returns a copy of the model currently held in the lpo data
+// structures.
+func saveModel() lpModel {
+
+	return copyModel(lpModel{Name: lpo.Name, ObjRow: lpo.ObjRow,
+		Rows: lpo.Rows, Cols: lpo.Cols, Elems: lpo.Elems})
+}
+
+//==============================================================================
+
+// restoreModel replaces the model held in the lpo data structures by a copy of
+// the model provided. The function returns no values.
+func restoreModel(model lpModel) {
+
+	dup := copyModel(model)
+
+	lpo.Name   = dup.Name
+	lpo.ObjRow = dup.ObjRow
+	lpo.Rows   = dup.Rows
+	lpo.Cols   = dup.Cols
+	lpo.Elems  = dup.Elems
+}
+
+//==============================================================================
+
+// readModelFile reads an MPS file and returns the model it contains, leaving
+// the model held in the lpo data structures, and its objective sense, unchanged.
+// In case of failure, function returns an error.
+func readModelFile(fileName string) (lpModel, error) {
+
+	current := saveModel()
+	defer restoreModel(current)
+
+	sense := objSense
+	defer func() { objSense = sense }()
+
+	if err := readMpsSense(fileName); err != nil {
+		return lpModel{}, errors.Wrapf(err, "Cannot read model from %s", fileName)
+	}
+
+	return saveModel(), nil
+}
+
+//==============================================================================
+
+// isPlinfy and isNeginf determine whether a bound is infinite, i.e. at or
+// beyond the values lpo uses for infinity.
+func isPlinfy(value float64) bool { return value >= lpo.Plinfy || math.IsInf(value,  1) }
+func isNeginf(value float64) bool { return value <= lpo.Neginf || math.IsInf(value, -1) }
+
+//==============================================================================
+
+// isFreeRow determines whether the row is a free (N) row, such as the objective
+// function, which does not constrain the model.
+func isFreeRow(row lpo.InputRow) bool {
+
+	return row.Type == "N"
+}
+
+//==============================================================================
+
+// isIntCol determines whether the column must take an integer value.
+func isIntCol(col lpo.InputCol) bool {
+
+	return col.Type == "I" || col.Type == "B"
+}
+
+//==============================================================================
+
+// objCoefs returns the objective function coefficient of every column of the
+// model, indexed by column.
+func objCoefs(model lpModel) []float64 {
+	var coefs = make([]float64, len(model.Cols))  // coefficients found
+
+	if model.ObjRow < 0 || model.ObjRow >= len(model.Rows) {
+		return coefs
+	}
+
+	for _, iElem := range model.Rows[model.ObjRow].HasElems {
+		elem := model.Elems[iElem]
+		coefs[elem.InCol] += elem.Value
+	}
+
+	return coefs
+}
+
+//==============================================================================
+
+// rowActivity returns the value of the left-hand side of a row of the model at
+// the point x, which is indexed by column.
+func rowActivity(model lpModel, iRow int, x []float64) float64 {
+	var lhs float64  // left-hand side accumulated
+
+	for _, iElem := range model.Rows[iRow].HasElems {
+		elem := model.Elems[iElem]
+		lhs += elem.Value * x[elem.InCol]
+	}
+
+	return lhs
+}
+
+//==============================================================================
+
+// subModel returns the model made up of the rows and columns of the model
+// specified, in the order given, with the elements lying in both. The objective
+// row is kept if it is among the rows, and is otherwise added without any
+// elements, so that the model can be written to an MPS file.
+func subModel(model lpModel, rows []int, cols []int) lpModel {
+	var sub    lpModel              // model being built
+	var newRow = make(map[int]int)  // index in sub of each row kept
+	var newCol = make(map[int]int)  // index in sub of each column kept
+
+	sub.Name   = model.Name
+	sub.ObjRow = -1
+
+	addRow := func(iRow int) {
+		row := model.Rows[iRow]
+		row.HasElems = nil
+		newRow[iRow] = len(sub.Rows)
+		sub.Rows     = append(sub.Rows, row)
+	}
+
+	if model.ObjRow >= 0 && model.ObjRow < len(model.Rows) {
+		addRow(model.ObjRow)
+		sub.ObjRow = 0
+	}
+	for _, iRow := range rows {
+		if _, ok := newRow[iRow]; !ok {
+			addRow(iRow)
+		}
+	}
+
+	for _, iCol := range cols {
+		col := model.Cols[iCol]
+		col.HasElems = nil
+		newCol[iCol] = len(sub.Cols)
+		sub.Cols     = append(sub.Cols, col)
+	}
+
+	for _, elem := range model.Elems {
+		iRow, okRow := newRow[elem.InRow]
+		iCol, okCol := newCol[elem.InCol]
+		if !okRow || !okCol {
+			continue
+		}
+		sub.Rows[iRow].HasElems = append(sub.Rows[iRow].HasElems, len(sub.Elems))
+		sub.Cols[iCol].HasElems = append(sub.Cols[iCol].HasElems, len(sub.Elems))
+		sub.Elems = append(sub.Elems, lpo.InputElem{InRow: iRow, InCol: iCol, Value: elem.Value})
+	}
+
+	return sub
+}
+
+//==============================================================================
+
+// findRow returns the index of the row of the model with the name specified, or
+// -1 if there is none.
+func findRow(model lpModel, name string) int {
+
+	for i, row := range model.Rows {
+		if row.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+//==============================================================================
+
+// findCol returns the index of the column of the model with the name specified,
+// or -1 if there is none.
+func findCol(model lpModel, name string) int {
+
+	for j, col := range model.Cols {
+		if col.Name == name {
+			return j
+		}
+	}
+
+	return -1
+}
+
+//==============================================================================
+
+// rhsBase returns the right-hand side of a row: the upper limit of an L row and
+// the lower limit of an E or G row.
+func rhsBase(row lpo.InputRow) float64 {
+
+	if row.Type == "L" {
+		return row.RHSup
+	}
+
+	return row.RHSlo
+}
+
+//==============================================================================
+
+// setRhs sets the right-hand side of a row, as returned by rhsBase, to the value.
+// Both finite limits are moved by the same amount, so that a range is kept. The
+// function returns no values.
+func setRhs(row *lpo.InputRow, value float64) {
+
+	delta := value - rhsBase(*row)
+	if !isNeginf(row.RHSlo) {
+		row.RHSlo += delta
+	}
+	if !isPlinfy(row.RHSup) {
+		row.RHSup += delta
+	}
+}
+
+//==============================================================================
+
+// setCoef sets the coefficient of a column in a row of the model, adding an
+// element if the column does not appear in the row. The function returns no
+// values.
+func setCoef(model *lpModel, iRow, iCol int, value float64) {
+
+	for _, iElem := range model.Rows[iRow].HasElems {
+		if model.Elems[iElem].InCol == iCol {
+			model.Elems[iElem].Value = value
+			return
+		}
+	}
+
+	iElem := len(model.Elems)
+	model.Elems = append(model.Elems, lpo.InputElem{InRow: iRow, InCol: iCol, Value: value})
+	model.Rows[iRow].HasElems = append(model.Rows[iRow].HasElems, iElem)
+	model.Cols[iCol].HasElems = append(model.Cols[iCol].HasElems, iElem)
+}
+
+//==============================================================================
+
+// setElems replaces the elements of the model with those provided, rebuilding
+// the element lists of every row and column. The function returns no values.
+func setElems(model *lpModel, elems []lpo.InputElem) {
+
+	for i := range model.Rows {
+		model.Rows[i].HasElems = nil
+	}
+	for j := range model.Cols {
+		model.Cols[j].HasElems = nil
+	}
+
+	model.Elems = elems
+	for iElem, elem := range elems {
+		model.Rows[elem.InRow].HasElems = append(model.Rows[elem.InRow].HasElems, iElem)
+		model.Cols[elem.InCol].HasElems = append(model.Cols[elem.InCol].HasElems, iElem)
+	}
+}
+
+//==============================================================================
+
+// contColType returns the type lpo uses for the continuous columns of the model,
+// taken from the first such column, or "R" if there is none.
+func contColType(model lpModel) string {
+
+	for _, col := range model.Cols {
+		if !isIntCol(col) {
+			return col.Type
+		}
+	}
+
+	return "R"
+}
@@ -0,0 +1,139 @@
+// This file contains the selection of the objective function among the free
+// (N) rows of the model, for MPS files holding several cost vectors.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+)
+
+//==============================================================================
+
+// freeRows returns the indexes of the free (N) rows of the model held in the
+// lpo data structures, in the order in which they appear.
+func freeRows() []int {
+	var rows []int  // free rows found
+
+	for i, row := range lpo.Rows {
+		if isFreeRow(row) {
+			rows = append(rows, i)
+		}
+	}
+
+	return rows
+}
+
+//==============================================================================
+
+// swapRows exchanges the positions of two rows in the lpo data structures,
+// updating the row index of every element of either row. The function returns
+// no values.
+func swapRows(iRow, jRow int) {
+
+	if iRow == jRow {
+		return
+	}
+
+	for _, iElem := range lpo.Rows[iRow].HasElems {
+		lpo.Elems[iElem].InRow = jRow
+	}
+	for _, iElem := range lpo.Rows[jRow].HasElems {
+		lpo.Elems[iElem].InRow = iRow
+	}
+
+	lpo.Rows[iRow], lpo.Rows[jRow] = lpo.Rows[jRow], lpo.Rows[iRow]
+}
+
+//==============================================================================
+
+// setObjRow makes the free row specified the objective function. MPS readers,
+// and lpo itself, take the first free row as the objective, so the row is moved
+// to the position of the first free row and lpo.ObjRow set to that position;
+// this way the choice is kept by WriteMpsFile and TransToGpx. Any solution
+// held is cleared, since it belongs to the previous objective.
+// In case of failure, function returns an error.
+func setObjRow(iRow int) error {
+
+	if iRow < 0 || iRow >= len(lpo.Rows) || !isFreeRow(lpo.Rows[iRow]) {
+		return errors.Errorf("Row %d is not a free row", iRow)
+	}
+
+	first := freeRows()[0]
+	swapRows(first, iRow)
+	lpo.ObjRow = first
+
+	clearLpoSoln()
+	return nil
+}
+
+//==============================================================================
+
+// clearLpoSoln clears the lpo and Cplex solutions, and the solution pool, held
+// by this program. The function accepts no arguments and returns no values.
+func clearLpoSoln() {
+
+	psResult = lpo.PsSoln{}
+	psPool   = nil
+	lpCpSoln = lpo.CplexSoln{}
+}
+
+//==============================================================================
+
+// wpObjRow lists the free rows of the model, with the number of non-zero
+// coefficients in each, and makes the row named on the command line, or
+// chosen by the user, the objective function.
+// In case of failure, function returns an error.
+func wpObjRow() error {
+	var rowName string  // name of the new objective row
+	var iRow    int     // index of the new objective row
+
+	rows := freeRows()
+	if len(rows) == 0 {
+		return errors.New("wpObjRow failed, model has no free rows")
+	}
+
+	if len(cmdArgs) == 0 {
+		fmt.Printf("\n%6s  %-16s %8s\n", "INDEX", "NAME", "ELEMS")
+		for _, i := range rows {
+			marker := ""
+			if i == lpo.ObjRow {
+				marker = "  <== objective"
+			}
+			fmt.Printf("%6d  %-16s %8d%s\n", i, lpo.Rows[i].Name, len(lpo.Rows[i].HasElems), marker)
+		}
+		fmt.Printf("\n")
+	}
+
+	rowName = promptArg(0, "Enter name of new objective row or <CR> to keep current: ")
+	if rowName == "" {
+		return nil
+	}
+
+	iRow = -1
+	for _, i := range rows {
+		if lpo.Rows[i].Name == rowName {
+			iRow = i
+			break
+		}
+	}
+	if iRow < 0 {
+		return errors.Errorf("wpObjRow failed, '%s' is not a free row", rowName)
+	}
+
+	if iRow == lpo.ObjRow {
+		fmt.Printf("Row '%s' is already the objective function.\n", rowName)
+		return nil
+	}
+
+	if err := setObjRow(iRow); err != nil {
+		return errors.Wrap(err, "wpObjRow failed")
+	}
+
+	fmt.Printf("Row '%s' is now the objective function, at index %d.\n", rowName, lpo.ObjRow)
+	fmt.Printf("The previous solution has been cleared.\n")
+
+	return nil
+}
@@ -0,0 +1,284 @@
+// This file contains the solver parameter store, which holds tolerances, limits
+// and other solver options set by the user and feeds them into every solve.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Environment variable naming a parameter file that Cplex reads whenever a
+// Cplex environment is opened, and the header line such a file must start with.
+
+const cplexParamEnv    = "ILOG_CPLEX_PARAMETER_FILE"
+const cplexParamHeader = "CPLEX Parameter File Version 12.6.0.0"
+
+// Prefix of parameter names passed to Cplex unchanged, e.g. cplex.CPX_PARAM_SCAIND.
+
+const cplexPassPrefix = "cplex."
+
+// paramDef describes a parameter known to the store.
+type paramDef struct {
+	Name   string  // name used to set the parameter
+	Cplex  string  // Cplex parameter it maps to, empty if none
+	IsInt  bool    // value must be an integer
+	Reach  string  // solve paths the parameter reaches
+	Desc   string  // description of the parameter
+}
+
+// Parameters known to the store. Coin-OR is run by lpo without any way to pass
+// options to it, so only MaxIter reaches the Coin-OR solve paths.
+
+var paramDefs = []paramDef{
+	{"maxiter", "",                  true,  "all lpo solves and ReduceMatrix", "lpo reduction passes (MaxIter)"},
+	{"opttol",  "CPX_PARAM_EPOPT",   false, "Cplex only",                      "optimality tolerance"},
+	{"feastol", "CPX_PARAM_EPRHS",   false, "Cplex only",                      "feasibility tolerance"},
+	{"mipgap",  "CPX_PARAM_EPGAP",   false, "Cplex only",                      "relative MIP gap"},
+	{"threads", "CPX_PARAM_THREADS", true,  "Cplex only",                      "number of threads"},
+	{"itlim",   "CPX_PARAM_ITLIM",   true,  "Cplex only",                      "simplex iteration limit"},
+	{"nodelim", "CPX_PARAM_NODELIM", true,  "Cplex only",                      "MIP node limit"},
+	{"timelim", "CPX_PARAM_TILIM",   false, "Cplex only",                      "Cplex time limit in seconds"},
+}
+
+// Parameter values set by the user, and a counter incremented on every change
+// so that a Cplex problem created before the change can be detected.
+
+var solverParams = make(map[string]string)
+var paramVersion int
+
+//==============================================================================
+
+// findParamDef returns the definition of the named parameter. Pass-through
+// Cplex parameters get a definition made up for them. If the name is unknown,
+// the function returns false.
+func findParamDef(name string) (paramDef, bool) {
+
+	for _, def := range paramDefs {
+		if def.Name == name {
+			return def, true
+		}
+	}
+
+	if strings.HasPrefix(name, cplexPassPrefix) && len(name) > len(cplexPassPrefix) {
+		return paramDef{Name: name, Cplex: name[len(cplexPassPrefix):], Reach: "Cplex only",
+			Desc: "Cplex parameter passed unchanged"}, true
+	}
+
+	return paramDef{}, false
+}
+
+//==============================================================================
+
+// setParam validates and stores the value of a parameter, or removes it if the
+// value is "default", and rewrites the Cplex parameter file.
+// In case of failure, function returns an error.
+func setParam(name, value string) error {
+
+	name = strings.ToLower(name)
+	if strings.HasPrefix(name, cplexPassPrefix) {
+		// Cplex parameter names are upper case.
+		name = cplexPassPrefix + strings.ToUpper(name[len(cplexPassPrefix):])
+	}
+
+	def, ok := findParamDef(name)
+	if !ok {
+		return errors.Errorf("Unknown parameter '%s'", name)
+	}
+
+	if value == "default" {
+		delete(solverParams, def.Name)
+	} else {
+		if def.IsInt {
+			if _, err := strconv.Atoi(value); err != nil {
+				return errors.Errorf("Parameter %s needs an integer, not '%s'", def.Name, value)
+			}
+		} else if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.Errorf("Parameter %s needs a number, not '%s'", def.Name, value)
+		}
+		solverParams[def.Name] = value
+	}
+
+	paramVersion++
+	return writeCplexParams()
+}
+
+//==============================================================================
+
+// writeCplexParams writes every parameter which maps to Cplex to a parameter
+// file in the lpo temp directory, and points Cplex at it through the
+// environment, so that every Cplex environment opened afterwards, whether by
+// lpo, gpx, or a Cplex executable started by lpo, uses the values. If no Cplex
+// parameter is set, the file is not used. In case of failure, function returns
+// an error.
+func writeCplexParams() error {
+	var lines   []string  // lines of the parameter file
+	var tempDir string    // lpo temp directory
+
+	for name, value := range solverParams {
+		if def, _ := findParamDef(name); def.Cplex != "" {
+			lines = append(lines, fmt.Sprintf("%-30s %s", def.Cplex, value))
+		}
+	}
+
+	if len(lines) == 0 {
+		return os.Unsetenv(cplexParamEnv)
+	}
+	sort.Strings(lines)
+
+	if err := lpo.GetTempDirPath(&tempDir); err != nil || tempDir == "" {
+		tempDir = os.TempDir()
+	}
+	fileName := filepath.Join(tempDir, "runopt_cplex.prm")
+
+	content := cplexParamHeader + "\n" + strings.Join(lines, "\n") + "\n"
+	if err := ioutil.WriteFile(fileName, []byte(content), 0644); err != nil {
+		return errors.Wrapf(err, "Cannot write Cplex parameter file %s", fileName)
+	}
+
+	return os.Setenv(cplexParamEnv, fileName)
+}
+
+//==============================================================================
+
+// paramMaxIter returns the MaxIter value to pass to lpo, which is the value set
+// by the user if there is one, or the default provided otherwise.
+func paramMaxIter(defaultIter int) int {
+
+	if value, ok := solverParams["maxiter"]; ok {
+		if maxIter, err := strconv.Atoi(value); err == nil {
+			return maxIter
+		}
+	}
+
+	return defaultIter
+}
+
+//==============================================================================
+
+// loadParams sets the parameters listed in a file, one "name value" pair per
+// line. Blank lines and lines starting with '#' are ignored. All lines are
+// checked, and the errors found are reported together.
+// In case of failure, function returns an error.
+func loadParams(fileName string) error {
+	var failed []string  // lines which could not be applied
+	var count   int      // number of parameters set
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot open parameter file %s", fileName)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 2 {
+			failed = append(failed, fmt.Sprintf("line %d: expected 'name value'", lineNum))
+			continue
+		}
+		if err = setParam(fields[0], fields[1]); err != nil {
+			failed = append(failed, fmt.Sprintf("line %d: %s", lineNum, err))
+			continue
+		}
+		count++
+	}
+
+	if err = scanner.Err(); err != nil {
+		return errors.Wrapf(err, "Cannot read parameter file %s", fileName)
+	}
+
+	fmt.Printf("%d parameters set from '%s'.\n", count, fileName)
+	if len(failed) > 0 {
+		return errors.Errorf("Errors in parameter file:\n  %s", strings.Join(failed, "\n  "))
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// wpShowParams prints every known parameter with its current value and the
+// solve paths it reaches, followed by any pass-through Cplex parameters set.
+// The function accepts no arguments and returns no values.
+func wpShowParams() {
+	var extra []string  // pass-through parameters set
+
+	fmt.Printf("\n%-10s %-12s %-32s %s\n", "NAME", "VALUE", "REACHES", "DESCRIPTION")
+	for _, def := range paramDefs {
+		value, ok := solverParams[def.Name]
+		if !ok {
+			value = "default"
+		}
+		fmt.Printf("%-10s %-12s %-32s %s\n", def.Name, value, def.Reach, def.Desc)
+	}
+
+	for name := range solverParams {
+		if strings.HasPrefix(name, cplexPassPrefix) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		fmt.Printf("%-10s %-12s %-32s %s\n", name, solverParams[name], "Cplex only",
+			"Cplex parameter passed unchanged")
+	}
+
+	if file := os.Getenv(cplexParamEnv); file != "" {
+		fmt.Printf("\nCplex parameters are read from '%s'.\n", file)
+	}
+
+}
+
+//==============================================================================
+
+// wpSetParam sets a parameter from the command line "set param name value",
+// prompting for anything missing. A value of "default" removes the setting.
+// In case of failure, function returns an error.
+func wpSetParam() error {
+
+	if len(cmdArgs) > 0 && cmdArgs[0] == "param" {
+		cmdArgs = cmdArgs[1:]
+	}
+
+	name  := promptArg(0, "Enter parameter name: ")
+	value := promptArg(1, "Enter parameter value or 'default': ")
+
+	if err := setParam(name, value); err != nil {
+		return errors.Wrap(err, "wpSetParam failed")
+	}
+
+	fmt.Printf("Parameter %s set to %s.\n", name, value)
+	return nil
+}
+
+//==============================================================================
+
+// wpLoadParams sets parameters from the file given on the command line
+// "load params file", prompting for the file if missing.
+// In case of failure, function returns an error.
+func wpLoadParams() error {
+
+	if len(cmdArgs) > 0 && cmdArgs[0] == "params" {
+		cmdArgs = cmdArgs[1:]
+	}
+
+	fileName := promptArg(0, "Enter parameter file name: ")
+	if err := loadParams(fileName); err != nil {
+		return errors.Wrap(err, "wpLoadParams failed")
+	}
+
+	return nil
+}
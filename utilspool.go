@@ -0,0 +1,464 @@
+// This file contains the MIP solution pool, which collects several distinct
+// integer solutions within a gap of the best one by adding no-good cuts and
+// re-solving, and the commands to list, display, compare and export them.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Prefix of the names of the rows added to the model while collecting the pool.
+
+const poolRowPref = "POOL_"
+
+// Defaults for the number of solutions collected and the relative gap allowed.
+
+const poolDefaultSize = 5
+const poolDefaultGap  = 0.05
+
+//==============================================================================
+
+// poolBinaries returns the binary columns of the model: the integer columns of
+// type B, or with bounds 0 and 1. The function also reports whether the model
+// has other integer columns.
+func poolBinaries(model lpModel) ([]int, bool) {
+	var bins    []int  // binary columns
+	var general bool   // model has general integer columns
+
+	for j, col := range model.Cols {
+		switch {
+		case !isIntCol(col):
+		case col.Type == "B" || (col.BndLo == 0 && col.BndUp == 1):
+			bins = append(bins, j)
+		default:
+			general = true
+		}
+	}
+
+	return bins, general
+}
+
+//==============================================================================
+
+// addPoolRow adds a row to the model with the limits and the coefficients
+// provided, indexed by column, skipping zeros. The row is named with the pool
+// prefix followed by the suffix, made unique if needed. The function returns
+// the name of the row added.
+func addPoolRow(model *lpModel, suffix string, lo, up float64, coefs map[int]float64) string {
+	var names = make(map[string]bool)  // row names in use
+
+	for _, row := range model.Rows {
+		names[row.Name] = true
+	}
+	name := poolRowPref + suffix
+	for k := 2; names[name]; k++ {
+		name = poolRowPref + suffix + "_" + strconv.Itoa(k)
+	}
+
+	rowType := "E"
+	switch {
+	case isNeginf(lo):
+		rowType = "L"
+	case isPlinfy(up):
+		rowType = "G"
+	}
+
+	iRow := len(model.Rows)
+	model.Rows = append(model.Rows, lpo.InputRow{Name: name, Type: rowType, RHSlo: lo, RHSup: up})
+
+	// Columns are added in order, so that the model is the same from run to run.
+	var cols []int  // columns with a coefficient in the row
+	for j := range coefs {
+		cols = append(cols, j)
+	}
+	sort.Ints(cols)
+	for _, j := range cols {
+		if coefs[j] != 0 {
+			setCoef(model, iRow, j, coefs[j])
+		}
+	}
+
+	return name
+}
+
+//==============================================================================
+
+// addNoGoodCut adds to the model the cut excluding the values of the binary
+// columns in the solution:
+//   sum (1 - x_j) over the columns at 1 + sum x_j over the columns at 0 >= 1
+// The function returns the name of the row added.
+func addNoGoodCut(model *lpModel, bins []int, soln lpo.PsSoln, suffix string) string {
+	var coefs = make(map[int]float64)  // coefficient of each binary column
+	var ones  float64                  // number of columns at 1
+
+	for _, j := range bins {
+		if soln.VarMap[model.Cols[j].Name].Value > 0.5 {
+			coefs[j] = -1
+			ones++
+		} else {
+			coefs[j] = 1
+		}
+	}
+
+	return addPoolRow(model, suffix, 1 - ones, lpo.Plinfy, coefs)
+}
+
+//==============================================================================
+
+// poolGap returns the relative gap between an objective value and the best one,
+// measured against the absolute value of the best, or 1 if that is smaller.
+func poolGap(objVal, best float64) float64 {
+
+	return math.Abs(objVal - best) / math.Max(math.Abs(best), 1)
+}
+
+//==============================================================================
+
+// collectPool solves the model and collects up to size distinct solutions of
+// the binary columns. Once the best solution is found, a row keeps the
+// objective within the relative gap of it, and after each solution a no-good
+// cut excludes it; the model is re-solved until size solutions are found or no
+// further solution exists. The rows added are removed from the solutions
+// returned. In case of failure, function returns an error, together with the
+// solutions found if the collection was interrupted.
+func collectPool(ctx context.Context, model lpModel, useCoin bool, size int,
+	gap float64) ([]lpo.PsSoln, error) {
+	var pool  []lpo.PsSoln  // solutions found, best first
+	var added []string      // rows added to the model
+
+	bins, _ := poolBinaries(model)
+	work    := copyModel(model)
+	psCtrl  := lpo.PsCtrl{RunSolver: true, MaxIter: paramMaxIter(10)}
+
+	for len(pool) < size {
+		var soln lpo.PsSoln  // solution of this solve
+
+		restoreModel(work)
+		err := solveInContext(ctx, useCoin, psCtrl, &soln)
+		switch {
+		case err == errInterrupted || err == errTimeLimit:
+			return pool, err
+		case err != nil && len(pool) == 0:
+			return nil, errors.Wrap(err, "Model could not be solved")
+		case err != nil:
+			// The cuts leave no further solution within the gap.
+			fmt.Printf("No further solution found (%s).\n", firstLine(err.Error()))
+			return pool, nil
+		}
+
+		for _, name := range added {
+			delete(soln.ConMap, name)
+		}
+		pool = append(pool, soln)
+		fmt.Printf("Solution %d: objective = %f\n", len(pool) - 1, soln.ObjVal)
+
+		if len(pool) == 1 {
+			best  := soln.ObjVal
+			allow := gap * math.Max(math.Abs(best), 1)
+			coefs := make(map[int]float64)
+			for j, coef := range objCoefs(model) {
+				coefs[j] = coef
+			}
+			if objSense == senseMax {
+				added = append(added, addPoolRow(&work, "OBJ", best - allow, lpo.Plinfy, coefs))
+			} else {
+				added = append(added, addPoolRow(&work, "OBJ", lpo.Neginf, best + allow, coefs))
+			}
+		}
+		added = append(added, addNoGoodCut(&work, bins, soln, "CUT" + strconv.Itoa(len(pool))))
+	}
+
+	return pool, nil
+}
+
+//==============================================================================
+
+// poolIndex returns the index of the pool solution given as the argument at the
+// position specified, or entered at the prompt. In case of failure, function
+// returns an error.
+func poolIndex(index int, prompt string) (int, error) {
+
+	userString := promptArg(index, prompt)
+	k, err := strconv.Atoi(userString)
+	if err != nil || k < 0 || k >= len(psPool) {
+		return 0, errors.Errorf("'%s' is not a solution of the pool (0 to %d)", userString,
+			len(psPool) - 1)
+	}
+
+	return k, nil
+}
+
+//==============================================================================
+
+// poolVarbNames returns the names of the variables of the pool solutions, in
+// alphabetical order.
+func poolVarbNames() []string {
+	var names []string  // variable names
+
+	for name := range psPool[0].VarMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+//==============================================================================
+
+// poolDiffers returns the names of the variables whose values differ between
+// two solutions of the pool.
+func poolDiffers(a, b lpo.PsSoln) []string {
+	var names []string  // variables which differ
+
+	for _, name := range poolVarbNames() {
+		if math.Abs(a.VarMap[name].Value - b.VarMap[name].Value) > verifyTol {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+//==============================================================================
+
+// wpPoolSolve collects the solution pool of the model held in the lpo data
+// structures, with the size and relative gap given on the command line as
+// "pool solve [size] [gap]" or entered at the prompt. The best solution also
+// becomes the lpo solution. The data structures are left unchanged.
+// In case of failure, function returns an error.
+func wpPoolSolve() error {
+	var size int      // number of solutions to collect
+	var gap  float64  // relative gap allowed
+	var err  error    // error returned by functions called
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+		return errors.New("wpPoolSolve failed, model not defined")
+	}
+	if lpo.ObjRow < 0 || lpo.ObjRow >= len(lpo.Rows) {
+		return errors.New("wpPoolSolve failed, model has no objective row")
+	}
+
+	model := saveModel()
+	bins, general := poolBinaries(model)
+	if len(bins) == 0 {
+		return errors.New("wpPoolSolve failed, model has no binary columns")
+	}
+	if general {
+		fmt.Printf("WARNING: Model has general integer columns, solutions are told apart\n")
+		fmt.Printf("by the values of the %d binary columns only.\n", len(bins))
+	}
+
+	size = poolDefaultSize
+	userString := promptArg(0, fmt.Sprintf("Enter number of solutions or <CR> for %d: ", size))
+	if userString != "" {
+		if size, err = strconv.Atoi(userString); err != nil || size < 1 {
+			return errors.Errorf("'%s' is not a valid number of solutions.", userString)
+		}
+	}
+
+	gap = poolDefaultGap
+	userString = promptArg(1, fmt.Sprintf("Enter relative gap or <CR> for %g: ", gap))
+	if userString != "" {
+		if gap, err = strconv.ParseFloat(userString, 64); err != nil || gap < 0 {
+			return errors.Errorf("'%s' is not a valid gap.", userString)
+		}
+	}
+
+	useCoin := wpGetUseCoin()
+	defer restoreModel(model)
+
+	ctx, endSolve := beginSolve(0)
+	defer endSolve()
+
+	fmt.Printf("Collecting up to %d solutions within %g of the best, press Ctrl-C to stop.\n",
+		size, gap)
+	pool, err := collectPool(ctx, model, useCoin, size, gap)
+	if len(pool) > 0 {
+		psPool   = pool
+		psResult = pool[0]
+		fmt.Printf("\n%d solutions in the pool, the best is now the lpo solution.\n", len(pool))
+	}
+	if err != nil {
+		return errors.Wrap(err, "wpPoolSolve failed")
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// wpPoolList lists the solutions of the pool with their objective values, their
+// gap to the best solution, and the number of variables differing from it.
+// The function accepts no arguments and returns no values.
+func wpPoolList() {
+
+	if len(psPool) == 0 {
+		fmt.Printf("Solution pool is empty.\n")
+		return
+	}
+
+	best := psPool[0].ObjVal
+	fmt.Printf("\n%6s %15s %15s %10s\n", "INDEX", "OBJECTIVE", "GAP", "DIFFERS")
+	for k, soln := range psPool {
+		fmt.Printf("%6d %15e %15e %10d\n", k, soln.ObjVal, poolGap(soln.ObjVal, best),
+			len(poolDiffers(psPool[0], soln)))
+	}
+
+}
+
+//==============================================================================
+
+// wpPoolShow prints the pool solution given on the command line or entered at
+// the prompt, as the lpo solution is printed.
+// In case of failure, function returns an error.
+func wpPoolShow() error {
+
+	if len(psPool) == 0 {
+		return errors.New("wpPoolShow failed, solution pool is empty")
+	}
+
+	k, err := poolIndex(0, "Enter index of solution: ")
+	if err != nil {
+		return errors.Wrap(err, "wpPoolShow failed")
+	}
+
+	fmt.Printf("\nSOLUTION %d, OBJECTIVE FUNCTION (%s) = %f\n", k, objSense, psPool[k].ObjVal)
+	printPsSoln(psPool[k])
+
+	return nil
+}
+
+//==============================================================================
+
+// wpPoolDiff lists the variables whose values differ between two solutions of
+// the pool, given on the command line or entered at the prompt.
+// In case of failure, function returns an error.
+func wpPoolDiff() error {
+
+	if len(psPool) == 0 {
+		return errors.New("wpPoolDiff failed, solution pool is empty")
+	}
+
+	a, err := poolIndex(0, "Enter index of first solution: ")
+	if err != nil {
+		return errors.Wrap(err, "wpPoolDiff failed")
+	}
+	b, err := poolIndex(1, "Enter index of second solution: ")
+	if err != nil {
+		return errors.Wrap(err, "wpPoolDiff failed")
+	}
+
+	solnA, solnB := psPool[a], psPool[b]
+	fmt.Printf("\nObjective: %f in solution %d, %f in solution %d.\n", solnA.ObjVal, a,
+		solnB.ObjVal, b)
+
+	names := poolDiffers(solnA, solnB)
+	if len(names) == 0 {
+		fmt.Printf("No variable differs.\n")
+		return nil
+	}
+
+	fmt.Printf("%d variables differ:\n\n", len(names))
+	fmt.Printf("%-16s %15s %15s\n", "NAME", "SOLUTION " + strconv.Itoa(a), "SOLUTION " + strconv.Itoa(b))
+	for _, name := range names {
+		fmt.Printf("%-16s %15e %15e\n", name, solnA.VarMap[name].Value, solnB.VarMap[name].Value)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// wpPoolExport writes the solutions of the pool to a CSV file, given on the
+// command line or entered at the prompt, with one column per solution and a
+// line for the objective followed by one line per variable.
+// In case of failure, function returns an error.
+func wpPoolExport() error {
+
+	if len(psPool) == 0 {
+		return errors.New("wpPoolExport failed, solution pool is empty")
+	}
+
+	fileName := promptArg(0, "Enter CSV file name: ")
+	if fileName == "" {
+		return errors.New("wpPoolExport failed, no file name given")
+	}
+	if custEnvOn {
+		fileName = dSrcDev + fileName + ".csv"
+	}
+
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	header    := []string{"name"}
+	objective := []string{"objective"}
+	for k, soln := range psPool {
+		header    = append(header, "solution_" + strconv.Itoa(k))
+		objective = append(objective, format(soln.ObjVal))
+	}
+	table := [][]string{header, objective}
+	for _, name := range poolVarbNames() {
+		line := []string{name}
+		for _, soln := range psPool {
+			line = append(line, format(soln.VarMap[name].Value))
+		}
+		table = append(table, line)
+	}
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "wpPoolExport failed to create file %s", fileName)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.WriteAll(table)
+	if err = w.Error(); err != nil {
+		return errors.Wrapf(err, "wpPoolExport failed to write file %s", fileName)
+	}
+
+	fmt.Printf("%d solutions written to '%s'.\n", len(psPool), fileName)
+	return nil
+}
+
+//==============================================================================
+
+// wpPool runs the solution pool command given on the command line as "pool
+// solve|list|show|diff|export", followed by the arguments of that command, or
+// entered at the prompt. In case of failure, function returns an error.
+func wpPool() error {
+
+	command := strings.ToLower(promptArg(0, "Enter pool command (solve|list|show|diff|export): "))
+	if len(cmdArgs) > 0 {
+		cmdArgs = cmdArgs[1:]
+	}
+
+	switch command {
+	case "solve":
+		return wpPoolSolve()
+	case "list":
+		wpPoolList()
+	case "show":
+		return wpPoolShow()
+	case "diff":
+		return wpPoolDiff()
+	case "export":
+		return wpPoolExport()
+	default:
+		return errors.Errorf("wpPool failed, unknown pool command '%s'", command)
+	}
+
+	return nil
+}
@@ -0,0 +1,637 @@
+// This file contains the reader of the PSOP files written by lpo, and the
+// offline postsolve, which recovers the solution of the original model from a
+// solution of the reduced model obtained elsewhere.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// psopElem is an element removed by a pre-solve operation, given by the names
+// of its row and column.
+type psopElem struct {
+	Row   string   // row name
+	Col   string   // column name
+	Value float64  // coefficient
+}
+
+// psopRecord is one pre-solve operation read from a PSOP file, with the rows
+// and columns it removed and their elements.
+type psopRecord struct {
+	Index  int              // position of the operation in the file, from 1
+	OpType string           // operation as written in the file
+	Rule   string           // reduction applied, named as in the presolve check
+	Rows   []lpo.InputRow   // rows removed, HasElems not set
+	Cols   []lpo.InputCol   // columns removed, HasElems not set
+	Elems  []psopElem       // elements of the rows and columns removed
+}
+
+// Reduction applied by each type of operation found in a PSOP file, named as in
+// the presolve check.
+
+var psopRules = map[string]string{
+	"DEL_ROW_NONBINDING": "DelRowNonbinding",
+	"DEL_ROW_SINGLETON":  "DelRowSingleton",
+	"DEL_COL_SINGLETON":  "DelColSingleton",
+	"DEL_FIXED_VAR":      "DelFixedVars",
+}
+
+// psopReplay holds the outcome of replaying the pre-solve operations on a
+// solution of the reduced model.
+type psopReplay struct {
+	Fixed     int                    // fixed columns given their value
+	Singleton int                    // singleton columns computed from their row
+	Unsolved  []string               // columns removed left without a value, and why
+	RowsDel   []lpo.InputRow         // rows deleted, in the order of the operations
+	RowElems  map[string][]psopElem  // elements of the rows deleted, by row
+}
+
+//==============================================================================
+
+// solnFormat returns the format of a solution file from its extension: "xml"
+// for a Cplex solution, "csv" for name and value pairs, or otherwise "coin".
+func solnFormat(fileName string) string {
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xml", ".sol":
+		return "xml"
+	case ".csv":
+		return "csv"
+	}
+
+	return "coin"
+}
+
+//==============================================================================
+
+// readCsvValues returns the values of the columns listed in a CSV file, one
+// "name,value" pair per line. Lines whose value is not a number, such as a
+// heading, are skipped. In case of failure, function returns an error.
+func readCsvValues(fileName string) (map[string]float64, error) {
+	var values = make(map[string]float64)  // value of each column
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot open solution file %s", fileName)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot read solution file %s", fileName)
+	}
+
+	for _, record := range records {
+		if len(record) < 2 {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			continue
+		}
+		values[strings.TrimSpace(record[0])] = value
+	}
+
+	return values, nil
+}
+
+//==============================================================================
+
+// readCoinValues returns the values of the columns in a solution file written
+// by the Coin-OR solvers with the "solu" command: a status line, followed by
+// one line per column holding its index, name, value and reduced cost, the
+// index being preceded by "**" if the column is infeasible.
+// In case of failure, function returns an error.
+func readCoinValues(fileName string) (map[string]float64, error) {
+	var values = make(map[string]float64)  // value of each column
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot open solution file %s", fileName)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "**"))
+		if len(fields) < 3 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			continue
+		}
+		values[fields[1]] = value
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "Cannot read solution file %s", fileName)
+	}
+
+	return values, nil
+}
+
+//==============================================================================
+
+// readReducedSoln returns the values of the columns in a solution file of the
+// format specified. In case of failure, function returns an error.
+func readReducedSoln(fileName, format string) (map[string]float64, error) {
+	var cpSoln lpo.CplexSoln  // Cplex solution parsed
+
+	switch format {
+	case "xml":
+		if err := lpo.CplexParseSoln(fileName, &cpSoln); err != nil {
+			return nil, errors.Wrapf(err, "Cannot parse Cplex solution %s", fileName)
+		}
+		values := make(map[string]float64)
+		for _, varb := range cpSoln.Varbs {
+			values[varb.Name] = varb.Value
+		}
+		return values, nil
+
+	case "csv":
+		return readCsvValues(fileName)
+
+	case "coin":
+		return readCoinValues(fileName)
+	}
+
+	return nil, errors.Errorf("Unknown solution format '%s'", format)
+}
+
+//==============================================================================
+
+// readPsopFile returns the pre-solve operations held in a PSOP file, in the
+// order they were applied. Each operation starts with a line holding its number,
+// counting from 1, and its type, one of the keys of psopRules, and is followed
+// by lines for the rows, columns and elements it removed:
+//   ROW  name type lo up
+//   COL  name type lo up
+//   ELEM row col value [row col value ...]
+// Infinite limits and bounds may be written as inf or -inf. Blank lines and
+// lines starting with '*' are skipped. Any other line, an operation out of
+// sequence or of an unknown type, and a file holding no operation are errors.
+// In case of failure, function returns an error.
+func readPsopFile(fileName string) ([]psopRecord, error) {
+	var records []psopRecord  // operations read
+	var lineNum int           // line being read
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot open PSOP file %s", fileName)
+	}
+	defer f.Close()
+
+	lineErr := func(format string, args ...interface{}) error {
+		return errors.Errorf("line %d of %s: %s", lineNum, fileName, fmt.Sprintf(format, args...))
+	}
+	number := func(field string) (float64, error) {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil || math.IsNaN(value) {
+			return 0, lineErr("'%s' is not a number", field)
+		}
+		return value, nil
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64 * 1024), 16 * 1024 * 1024)
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '*' {
+			continue
+		}
+		fields := strings.Fields(line)
+
+		switch fields[0] {
+		case "ROW", "COL":
+			if len(records) == 0 {
+				return nil, lineErr("%s before the first operation", fields[0])
+			}
+			if len(fields) != 5 {
+				return nil, lineErr("expected %s name type lo up", fields[0])
+			}
+			lo, err := number(fields[3])
+			if err != nil {
+				return nil, err
+			}
+			up, err := number(fields[4])
+			if err != nil {
+				return nil, err
+			}
+			record := &records[len(records) - 1]
+			if fields[0] == "ROW" {
+				record.Rows = append(record.Rows, lpo.InputRow{Name: fields[1], Type: fields[2],
+					RHSlo: lo, RHSup: up})
+			} else {
+				record.Cols = append(record.Cols, lpo.InputCol{Name: fields[1], Type: fields[2],
+					BndLo: lo, BndUp: up})
+			}
+
+		case "ELEM":
+			if len(records) == 0 {
+				return nil, lineErr("ELEM before the first operation")
+			}
+			if len(fields) == 1 || (len(fields) - 1) % 3 != 0 {
+				return nil, lineErr("expected ELEM row col value ...")
+			}
+			record := &records[len(records) - 1]
+			for k := 1; k < len(fields); k += 3 {
+				value, err := number(fields[k + 2])
+				if err != nil {
+					return nil, err
+				}
+				record.Elems = append(record.Elems, psopElem{fields[k], fields[k + 1], value})
+			}
+
+		default:
+			index, err := strconv.Atoi(fields[0])
+			if err != nil || len(fields) != 2 {
+				return nil, lineErr("not a PSOP record: '%s'", line)
+			}
+			if index != len(records) + 1 {
+				return nil, lineErr("operation %d found where %d was expected", index, len(records) + 1)
+			}
+			rule, ok := psopRules[fields[1]]
+			if !ok {
+				return nil, lineErr("unknown operation '%s'", fields[1])
+			}
+			records = append(records, psopRecord{Index: index, OpType: fields[1], Rule: rule})
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "Cannot read PSOP file %s", fileName)
+	}
+	if len(records) == 0 {
+		return nil, errors.Errorf("No pre-solve operations found in PSOP file %s", fileName)
+	}
+
+	return records, nil
+}
+
+//==============================================================================
+
+// singletonValue returns the value of a singleton column which makes its row,
+// whose elements are given, hold with the other columns at their values. If the
+// row is an equality, the value solves it; otherwise it is the end of the range
+// allowed by the limits of the row and the bounds of the column which is best
+// for the objective coefficient of the column, cost, under the objective sense
+// of the model, or the value nearest zero in the range if cost is zero.
+// If the value of another column is not known, if the range is empty, or if
+// the best end is infinite, function returns an error.
+func singletonValue(col lpo.InputCol, row lpo.InputRow, elems []psopElem, cost float64,
+	values map[string]float64) (float64, error) {
+	var coef float64  // coefficient of the column in the row
+	var rest float64  // activity of the other columns
+
+	for _, elem := range elems {
+		if elem.Col == col.Name {
+			coef += elem.Value
+			continue
+		}
+		value, ok := values[elem.Col]
+		if !ok {
+			return 0, errors.Errorf("value of column %s in row %s not known", elem.Col, row.Name)
+		}
+		rest += elem.Value * value
+	}
+	if coef == 0 {
+		return 0, errors.Errorf("no coefficient in row %s", row.Name)
+	}
+
+	lo, up := math.Inf(-1), math.Inf(1)
+	if !isNeginf(row.RHSlo) {
+		if coef > 0 {
+			lo = (row.RHSlo - rest) / coef
+		} else {
+			up = (row.RHSlo - rest) / coef
+		}
+	}
+	if !isPlinfy(row.RHSup) {
+		if coef > 0 {
+			up = (row.RHSup - rest) / coef
+		} else {
+			lo = (row.RHSup - rest) / coef
+		}
+	}
+	if !isNeginf(col.BndLo) {
+		lo = math.Max(lo, col.BndLo)
+	}
+	if !isPlinfy(col.BndUp) {
+		up = math.Min(up, col.BndUp)
+	}
+	if lo > up + verifyTol * math.Max(1, math.Abs(up)) {
+		return 0, errors.Errorf("row %s and the bounds of the column allow no value (%g > %g)",
+			row.Name, lo, up)
+	}
+	if lo > up {
+		lo = up
+	}
+
+	// Under minimization a positive cost wants the lowest value.
+	if objSense == senseMax {
+		cost = -cost
+	}
+	value := math.Max(lo, math.Min(up, 0))
+	switch {
+	case cost > 0:
+		value = lo
+	case cost < 0:
+		value = up
+	}
+	if math.IsInf(value, 0) {
+		return 0, errors.Errorf("objective is unbounded in the column within row %s", row.Name)
+	}
+
+	return value, nil
+}
+
+//==============================================================================
+
+// replayPsop replays the pre-solve operations in reverse order on the values of
+// the columns of the reduced model, adding the values of the columns removed:
+// fixed columns take their fixed value, and singleton columns the value given
+// by the row removed with them, their objective coefficient being their element
+// in a row the operation does not delete. Rows deleted are collected so that
+// their activity can be found once all columns have a value.
+func replayPsop(records []psopRecord, values map[string]float64) psopReplay {
+	var replay = psopReplay{RowElems: make(map[string][]psopElem)}
+
+	// A row is taken with the elements listed by the operation deleting it, as
+	// they were when it was deleted.
+	for _, record := range records {
+		for _, row := range record.Rows {
+			for _, elem := range record.Elems {
+				if elem.Row == row.Name {
+					replay.RowElems[row.Name] = append(replay.RowElems[row.Name], elem)
+				}
+			}
+		}
+		replay.RowsDel = append(replay.RowsDel, record.Rows...)
+	}
+
+	for k := len(records) - 1; k >= 0; k-- {
+		record := records[k]
+		for _, col := range record.Cols {
+			if _, ok := values[col.Name]; ok {
+				continue
+			}
+			switch record.Rule {
+			case "DelFixedVars":
+				values[col.Name] = col.BndLo
+				replay.Fixed++
+
+			case "DelColSingleton":
+				inRow := make(map[string]bool)  // rows removed with the column
+				for _, row := range record.Rows {
+					inRow[row.Name] = true
+				}
+				cost := 0.0  // objective coefficient of the column
+				for _, elem := range record.Elems {
+					if elem.Col == col.Name && !inRow[elem.Row] {
+						cost += elem.Value
+					}
+				}
+
+				var reasons []string  // why each row gave no value
+				for _, row := range record.Rows {
+					value, err := singletonValue(col, row, replay.RowElems[row.Name], cost, values)
+					if err == nil {
+						values[col.Name] = value
+						replay.Singleton++
+						reasons = nil
+						break
+					}
+					reasons = append(reasons, err.Error())
+				}
+				if _, ok := values[col.Name]; !ok {
+					if len(record.Rows) == 0 {
+						reasons = append(reasons, "no row removed with it")
+					}
+					replay.Unsolved = append(replay.Unsolved, fmt.Sprintf("%s (operation %d): %s",
+						col.Name, record.Index, strings.Join(reasons, "; ")))
+				}
+
+			default:
+				replay.Unsolved = append(replay.Unsolved, fmt.Sprintf("%s (operation %d): removed by %s",
+					col.Name, record.Index, record.OpType))
+			}
+		}
+	}
+
+	return replay
+}
+
+//==============================================================================
+
+// postsolveModel returns the model with every column given a value fixed at it,
+// and the indexes of the columns of the model which were given no value.
+func postsolveModel(model lpModel, values map[string]float64) (lpModel, []int) {
+	var missing []int  // columns without a value
+
+	work := copyModel(model)
+	for j := range work.Cols {
+		value, ok := values[work.Cols[j].Name]
+		if !ok {
+			missing = append(missing, j)
+			continue
+		}
+		work.Cols[j].BndLo, work.Cols[j].BndUp = value, value
+	}
+
+	return work, missing
+}
+
+//==============================================================================
+
+// wpPostsolve recovers the full solution from a solution of the reduced model,
+// read from a Cplex XML solution, a Coin-OR solution or a CSV file, and the PSOP
+// file written when the model was reduced, given on the command line as
+// "postsolve [soln] [psop|-] [format]" or entered at the prompt. The operations
+// of the PSOP file are replayed in reverse order to give the columns removed.
+// If the original model is held in the lpo data structures, any column still
+// without a value is found by solving the model with all other columns fixed,
+// and the solution is checked against the model. The solution recovered becomes
+// the lpo solution. In case of failure, function returns an error.
+func wpPostsolve() error {
+	var fileCsv string      // CSV output file
+	var soln    lpo.PsSoln  // solution recovered
+	var names   []string    // columns of the solution, in output order
+	var solved  int         // columns found by the solve
+	var err     error       // error returned by functions called
+
+	fileName := promptArg(0, "Enter solution file of the reduced model: ")
+	filePsop := promptArg(1, "Enter PSOP file name or <CR> for none: ")
+	if filePsop == "-" {
+		filePsop = ""
+	}
+	format := solnFormat(fileName)
+	if len(cmdArgs) > 2 {
+		format = strings.ToLower(cmdArgs[2])
+	}
+
+	values, err := readReducedSoln(fileName, format)
+	if err != nil {
+		return errors.Wrap(err, "wpPostsolve failed")
+	}
+	numRead := len(values)
+	fmt.Printf("%d columns read from '%s' (%s).\n", numRead, fileName, format)
+
+	if filePsop != "" {
+		if custEnvOn {
+			filePsop = dSrcDev + fPrefPsopOut + filePsop + fExtension
+		}
+		records, err := readPsopFile(filePsop)
+		if err != nil {
+			return errors.Wrap(err, "wpPostsolve failed")
+		}
+		replay := replayPsop(records, values)
+		fmt.Printf("%d operations replayed from '%s': %d fixed columns, %d singleton columns (%s),\n",
+			len(records), filePsop, replay.Fixed, replay.Singleton, objSense)
+		fmt.Printf("%d rows deleted.\n", len(replay.RowsDel))
+		if len(replay.Unsolved) > 0 {
+			fmt.Printf("%d columns removed by the reductions were not given a value:\n", len(replay.Unsolved))
+			for k, unsolved := range replay.Unsolved {
+				if k == pauseAfter {
+					fmt.Printf("  ... and %d more\n", len(replay.Unsolved) - k)
+					break
+				}
+				fmt.Printf("  %s\n", unsolved)
+			}
+			if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 {
+				return errors.Errorf("wpPostsolve failed, %d columns have no value and no original model is held to solve for them",
+					len(replay.Unsolved))
+			}
+		}
+
+		soln.ConMap = make(map[string]lpo.PsSolnCon)
+		for _, row := range replay.RowsDel {
+			var activity float64  // activity of the row
+			known := true
+			for _, elem := range replay.RowElems[row.Name] {
+				value, ok := values[elem.Col]
+				known = known && ok
+				activity += elem.Value * value
+			}
+			if known {
+				soln.ConMap[row.Name] = lpo.PsSolnCon{Type: row.Type, Rhs: rhsBase(row),
+					Slack: rhsBase(row) - activity}
+			}
+		}
+	}
+
+	haveModel := len(lpo.Rows) > 0 && len(lpo.Cols) > 0
+	if haveModel {
+		model := saveModel()
+		work, missing := postsolveModel(model, values)
+		if len(missing) == len(model.Cols) {
+			return errors.New("wpPostsolve failed, no column of the model is in the solution")
+		}
+
+		// Columns the PSOP file does not cover are found by a solve, unless fixed.
+		for _, j := range missing {
+			if work.Cols[j].BndLo == work.Cols[j].BndUp {
+				values[work.Cols[j].Name] = work.Cols[j].BndLo
+			} else {
+				solved++
+			}
+		}
+		if solved > 0 {
+			var restricted lpo.PsSoln  // solution of the model with known columns fixed
+
+			fmt.Printf("%d columns are not covered by the solution and PSOP files, and are found by\n", solved)
+			fmt.Printf("solving the original model with all other columns fixed.\n")
+			useCoin := wpGetUseCoin()
+			restoreModel(work)
+			psCtrl := lpo.PsCtrl{RunSolver: true, MaxIter: paramMaxIter(10)}
+			err = solveWithCancel(0, useCoin, psCtrl, &restricted)
+			restoreModel(model)
+			if err != nil {
+				return errors.Wrap(err, "wpPostsolve failed, the reduced solution does not extend to the model")
+			}
+			for _, j := range missing {
+				name := work.Cols[j].Name
+				if _, ok := values[name]; !ok {
+					values[name] = restricted.VarMap[name].Value
+				}
+			}
+		}
+
+		for _, col := range model.Cols {
+			names = append(names, col.Name)
+		}
+		soln.VarMap = make(map[string]lpo.PsSolnVar)
+		for _, name := range names {
+			soln.VarMap[name] = lpo.PsSolnVar{Value: values[name]}
+		}
+		for j, coef := range objCoefs(model) {
+			soln.ObjVal += coef * values[model.Cols[j].Name]
+		}
+
+		fmt.Printf("Recovered %d columns: %d from the solution file, %d from the PSOP file or fixed, %d by solving.\n",
+			len(model.Cols), len(model.Cols) - len(missing), len(missing) - solved, solved)
+		fmt.Printf("\nOBJECTIVE FUNCTION (%s) = %f\n", objSense, soln.ObjVal)
+		printVerify(verifySolution(model, soln, verifyTol), false)
+	} else {
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		soln.VarMap = make(map[string]lpo.PsSolnVar)
+		for _, name := range names {
+			soln.VarMap[name] = lpo.PsSolnVar{Value: values[name]}
+		}
+		fmt.Printf("Recovered %d columns: %d from the solution file, %d from the PSOP file.\n", len(names),
+			numRead, len(names) - numRead)
+		fmt.Printf("No original model is held, so the objective is not computed and the solution is not checked.\n")
+	}
+
+	psResult = soln
+	fmt.Printf("The recovered solution is now the lpo solution; it holds no duals.\n")
+
+	fmt.Printf("\nEnter CSV file name for the solution or <CR> for none: ")
+	fmt.Scanln(&fileCsv)
+	if fileCsv == "" {
+		return nil
+	}
+	if custEnvOn {
+		fileCsv = dSrcDev + fileCsv + ".csv"
+	}
+
+	f, err := os.Create(fileCsv)
+	if err != nil {
+		return errors.Wrapf(err, "wpPostsolve failed to create file %s", fileCsv)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"name", "value"})
+	for _, name := range names {
+		w.Write([]string{name, strconv.FormatFloat(values[name], 'g', -1, 64)})
+	}
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return errors.Wrapf(err, "wpPostsolve failed to write file %s", fileCsv)
+	}
+	fmt.Printf("Solution written to '%s'.\n", fileCsv)
+
+	return nil
+}
@@ -0,0 +1,855 @@
+// This file contains the sensitivity and ranging report for an LP solution,
+// giving the range of each objective coefficient and right-hand side over which
+// the optimal basis does not change.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Name of the Cplex interactive optimizer, searched for in the path, which is
+// used to obtain ranges from Cplex.
+
+const cplexExe = "cplex"
+
+// Largest number of constraint rows for which ranges are computed from the
+// basis, since the basis matrix is held as a dense matrix.
+
+const rangeMaxRows = 3000
+
+// Tolerance used to decide whether a value is at one of its bounds, and to
+// discard negligible pivots.
+
+const rangeTol = 1.0e-7
+
+// costRange holds the range of the objective coefficient of a column.
+type costRange struct {
+	Name    string   // column name
+	Status  string   // "BS" basic, "LL" at lower bound, "UL" at upper bound
+	Value   float64  // value in the solution
+	Cost    float64  // current objective coefficient
+	RedCost float64  // reduced cost
+	Lo      float64  // lowest coefficient keeping the basis optimal
+	Up      float64  // highest coefficient keeping the basis optimal
+}
+
+// rhsRange holds the range of the right-hand side of a row.
+type rhsRange struct {
+	Name     string   // row name
+	Type     string   // row type
+	Status   string   // "BS" if the row is not binding, "LL" or "UL" otherwise
+	Activity float64  // value of the left-hand side in the solution
+	Rhs      float64  // right-hand side ranged: the bound at which the row is binding
+	Dual     float64  // dual value
+	Lo       float64  // lowest right-hand side for which the dual is valid
+	Up       float64  // highest right-hand side for which the dual is valid
+}
+
+// rangeReport holds the ranges of an LP solution.
+type rangeReport struct {
+	Source   string       // where the ranges came from
+	Sense    string       // objective sense the ranges refer to
+	Costs    []costRange  // ranges of objective coefficients
+	Rhs      []rhsRange   // ranges of right-hand sides
+	Warnings []string     // conditions which make the ranges less reliable
+}
+
+// rangeVar is a variable of the model in the form used to compute ranges, where
+// each constraint row i is given a logical variable r(i) = a(i)x, bounded by the
+// row limits, so that the model is min cx subject to Ax - r = 0 and bounds.
+type rangeVar struct {
+	Name   string     // column or row name
+	Lo     float64    // lower bound
+	Up     float64    // upper bound
+	Value  float64    // value in the solution
+	Cost   float64    // objective coefficient in minimization form
+	Hint   float64    // reduced cost or dual reported by the solver
+	Rows   []int      // constraint rows of the non-zero entries
+	Coefs  []float64  // values of the non-zero entries
+}
+
+//==============================================================================
+
+// boundStatus returns the status of a value with respect to its bounds: "LL"
+// or "UL" if the value is at the lower or upper bound, and "BS" otherwise.
+func boundStatus(value, lo, up float64) string {
+
+	if !isNeginf(lo) && math.Abs(value - lo) <= rangeTol * (1 + math.Abs(lo)) {
+		return "LL"
+	}
+	if !isPlinfy(up) && math.Abs(value - up) <= rangeTol * (1 + math.Abs(up)) {
+		return "UL"
+	}
+
+	return "BS"
+}
+
+//==============================================================================
+
+// denseLU is the LU factorization with partial pivoting of a square matrix,
+// such that PA = LU, where L has a unit diagonal and is stored below the
+// diagonal of a, and U is stored on and above it.
+type denseLU struct {
+	a    [][]float64  // factors
+	perm []int        // row i of PA is row perm[i] of A
+}
+
+//==============================================================================
+
+// factorLU returns the LU factorization of the matrix, which it overwrites. If
+// the matrix is singular, function returns an error.
+func factorLU(a [][]float64) (*denseLU, error) {
+	var n = len(a)  // order of the matrix
+
+	lu := &denseLU{a: a, perm: make([]int, n)}
+	for i := range lu.perm {
+		lu.perm[i] = i
+	}
+
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(a[i][k]) > math.Abs(a[p][k]) {
+				p = i
+			}
+		}
+		if math.Abs(a[p][k]) < 1.0e-12 {
+			return nil, errors.New("Basis matrix is singular")
+		}
+		a[k], a[p] = a[p], a[k]
+		lu.perm[k], lu.perm[p] = lu.perm[p], lu.perm[k]
+
+		for i := k + 1; i < n; i++ {
+			a[i][k] /= a[k][k]
+			if a[i][k] == 0 {
+				continue
+			}
+			for j := k + 1; j < n; j++ {
+				a[i][j] -= a[i][k] * a[k][j]
+			}
+		}
+	}
+
+	return lu, nil
+}
+
+//==============================================================================
+
+// solve returns x such that Ax = b.
+func (lu *denseLU) solve(b []float64) []float64 {
+	var n = len(b)             // order of the matrix
+	var x = make([]float64, n) // solution
+
+	for i := 0; i < n; i++ {
+		x[i] = b[lu.perm[i]]
+		for j := 0; j < i; j++ {
+			x[i] -= lu.a[i][j] * x[j]
+		}
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			x[i] -= lu.a[i][j] * x[j]
+		}
+		x[i] /= lu.a[i][i]
+	}
+
+	return x
+}
+
+//==============================================================================
+
+// solveT returns x such that A'x = b, where A' is the transpose of A.
+func (lu *denseLU) solveT(b []float64) []float64 {
+	var n = len(b)             // order of the matrix
+	var w = make([]float64, n) // intermediate solution
+	var x = make([]float64, n) // solution
+
+	for i := 0; i < n; i++ {
+		w[i] = b[i]
+		for j := 0; j < i; j++ {
+			w[i] -= lu.a[j][i] * w[j]
+		}
+		w[i] /= lu.a[i][i]
+	}
+
+	for i := n - 1; i >= 0; i-- {
+		for j := i + 1; j < n; j++ {
+			w[i] -= lu.a[j][i] * w[j]
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		x[lu.perm[i]] = w[i]
+	}
+
+	return x
+}
+
+//==============================================================================
+
+// rangeVars returns the structural variables of the model, followed by one
+// logical variable for each constraint row, with the values taken from the
+// solution, together with the indexes of the constraint rows in the model.
+// In case of failure, function returns an error.
+func rangeVars(model lpModel, soln lpo.PsSoln, sense string) ([]rangeVar, []int, error) {
+	var vars    []rangeVar            // variables built
+	var cons    []int                 // model indexes of constraint rows
+	var rowPos  = make(map[int]int)   // position of each constraint row
+	var x       []float64             // solution value of each column
+
+	for i, row := range model.Rows {
+		if !isFreeRow(row) {
+			rowPos[i] = len(cons)
+			cons = append(cons, i)
+		}
+	}
+
+	costs := objCoefs(model)
+	x      = make([]float64, len(model.Cols))
+	for j, col := range model.Cols {
+		varb, ok := soln.VarMap[col.Name]
+		if !ok {
+			return nil, nil, errors.Errorf("Column '%s' has no value in the solution", col.Name)
+		}
+		x[j] = varb.Value
+
+		v := rangeVar{Name: col.Name, Lo: col.BndLo, Up: col.BndUp, Value: varb.Value,
+			Cost: costs[j], Hint: varb.ReducedCost}
+		if sense == senseMax {
+			v.Cost = -v.Cost
+		}
+		for _, iElem := range col.HasElems {
+			elem := model.Elems[iElem]
+			if k, ok := rowPos[elem.InRow]; ok {
+				v.Rows  = append(v.Rows,  k)
+				v.Coefs = append(v.Coefs, elem.Value)
+			}
+		}
+		vars = append(vars, v)
+	}
+
+	for k, i := range cons {
+		row := model.Rows[i]
+		vars = append(vars, rangeVar{Name: row.Name, Lo: row.RHSlo, Up: row.RHSup,
+			Value: rowActivity(model, i, x), Hint: soln.ConMap[row.Name].Pi,
+			Rows: []int{k}, Coefs: []float64{-1}})
+	}
+
+	return vars, cons, nil
+}
+
+//==============================================================================
+
+// findBasis chooses m variables forming a basis consistent with the solution.
+// Variables strictly between their bounds must be basic; the remaining ones
+// are taken from the variables at a bound, those with the smallest reduced cost
+// or dual reported by the solver first, skipping any which are linearly
+// dependent on the ones already chosen. In case of failure, function returns
+// an error.
+func findBasis(vars []rangeVar, m int) ([]int, error) {
+	var basis   []int         // variables chosen
+	var pivots  []int         // pivot row of each reduced column
+	var reduced [][]float64   // chosen columns reduced to echelon form
+	var atBound []int         // variables at a bound
+
+	// addColumn adds the variable to the basis if its column is independent
+	// of the columns already chosen.
+	addColumn := func(iVar int) bool {
+		v := make([]float64, m)
+		for i, k := range vars[iVar].Rows {
+			v[k] += vars[iVar].Coefs[i]
+		}
+		for i, p := range pivots {
+			if f := v[p]; f != 0 {
+				for r := range v {
+					v[r] -= f * reduced[i][r]
+				}
+			}
+		}
+
+		p := -1
+		for r := range v {
+			if p < 0 || math.Abs(v[r]) > math.Abs(v[p]) {
+				p = r
+			}
+		}
+		if p < 0 || math.Abs(v[p]) < 1.0e-9 {
+			return false
+		}
+
+		f := v[p]
+		for r := range v {
+			v[r] /= f
+		}
+		pivots  = append(pivots, p)
+		reduced = append(reduced, v)
+		basis   = append(basis, iVar)
+		return true
+	}
+
+	for iVar, v := range vars {
+		if boundStatus(v.Value, v.Lo, v.Up) != "BS" {
+			atBound = append(atBound, iVar)
+			continue
+		}
+		if !addColumn(iVar) {
+			return nil, errors.Errorf("Solution is not basic, '%s' is between its bounds", v.Name)
+		}
+	}
+
+	// Fixed variables never enter the basis unless they have to.
+	sort.SliceStable(atBound, func(a, b int) bool {
+		va, vb := vars[atBound[a]], vars[atBound[b]]
+		if (va.Lo == va.Up) != (vb.Lo == vb.Up) {
+			return vb.Lo == vb.Up
+		}
+		return math.Abs(va.Hint) < math.Abs(vb.Hint)
+	})
+
+	for _, iVar := range atBound {
+		if len(basis) == m {
+			break
+		}
+		addColumn(iVar)
+	}
+
+	if len(basis) < m {
+		return nil, errors.New("Basis could not be identified from the solution")
+	}
+
+	return basis, nil
+}
+
+//==============================================================================
+
+// computeRanges computes the ranges of an LP solution from an optimal basis
+// identified from the solution values, working with the minimization form of
+// the model and converting the results back to the sense given.
+// In case of failure, function returns an error.
+func computeRanges(model lpModel, soln lpo.PsSoln, sense string) (rangeReport, error) {
+	var report  rangeReport  // ranges computed
+	var isBasic []bool       // variable is in the basis
+	var d       []float64    // reduced cost of each variable
+	var dualInf int          // number of reduced costs with the wrong sign
+
+	report.Source = "computed from the optimal basis"
+	report.Sense  = sense
+
+	vars, cons, err := rangeVars(model, soln, sense)
+	if err != nil {
+		return report, err
+	}
+	m := len(cons)
+	n := len(model.Cols)
+	if m == 0 {
+		return report, errors.New("Model has no constraint rows")
+	}
+	if m > rangeMaxRows {
+		return report, errors.Errorf("Model has %d rows, ranges are only computed up to %d",
+			m, rangeMaxRows)
+	}
+
+	basis, err := findBasis(vars, m)
+	if err != nil {
+		return report, err
+	}
+
+	isBasic = make([]bool, len(vars))
+	bMat   := make([][]float64, m)
+	for r := range bMat {
+		bMat[r] = make([]float64, m)
+	}
+	cB := make([]float64, m)
+	for p, iVar := range basis {
+		isBasic[iVar] = true
+		cB[p] = vars[iVar].Cost
+		for i, k := range vars[iVar].Rows {
+			bMat[k][p] += vars[iVar].Coefs[i]
+		}
+	}
+
+	lu, err := factorLU(bMat)
+	if err != nil {
+		return report, err
+	}
+
+	// Duals y solve B'y = cB, and the reduced cost of each variable is c - a'y.
+	y := lu.solveT(cB)
+	d  = make([]float64, len(vars))
+	for iVar, v := range vars {
+		d[iVar] = v.Cost
+		for i, k := range v.Rows {
+			d[iVar] -= v.Coefs[i] * y[k]
+		}
+		if isBasic[iVar] {
+			d[iVar] = 0
+			continue
+		}
+		status := boundStatus(v.Value, v.Lo, v.Up)
+		if (status == "LL" && d[iVar] < -rangeTol) || (status == "UL" && d[iVar] > rangeTol) {
+			dualInf++
+		}
+	}
+	if dualInf > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d reduced costs have the "+
+			"wrong sign for the basis identified, so the solution may be degenerate.", dualInf))
+	}
+
+	// sign converts values from the minimization form back to the sense given.
+	sign := 1.0
+	if sense == senseMax {
+		sign = -1.0
+	}
+
+	// Objective ranges. Changing the cost of the basic variable in position p
+	// by delta changes the reduced cost of nonbasic variable k by -delta*alpha,
+	// where alpha is row p of the inverse of B times the column of k.
+	pos := make(map[int]int)
+	for p, iVar := range basis {
+		pos[iVar] = p
+	}
+
+	for j := 0; j < n; j++ {
+		v      := vars[j]
+		lo, up := math.Inf(-1), math.Inf(1)
+		status := boundStatus(v.Value, v.Lo, v.Up)
+
+		switch {
+		case isBasic[j]:
+			status = "BS"
+			ep := make([]float64, m)
+			ep[pos[j]] = 1
+			z := lu.solveT(ep)
+			for k, w := range vars {
+				if isBasic[k] || w.Lo == w.Up {
+					continue
+				}
+				alpha := 0.0
+				for i, r := range w.Rows {
+					alpha += w.Coefs[i] * z[r]
+				}
+				if math.Abs(alpha) < 1.0e-9 {
+					continue
+				}
+				if boundStatus(w.Value, w.Lo, w.Up) == "UL" {
+					// d - delta*alpha must stay at or below zero.
+					limit := math.Min(d[k], 0) / alpha
+					if alpha > 0 {
+						lo = math.Max(lo, limit)
+					} else {
+						up = math.Min(up, limit)
+					}
+				} else {
+					// d - delta*alpha must stay at or above zero.
+					limit := math.Max(d[k], 0) / alpha
+					if alpha > 0 {
+						up = math.Min(up, limit)
+					} else {
+						lo = math.Max(lo, limit)
+					}
+				}
+			}
+		case v.Lo == v.Up:
+			// A fixed column keeps its value whatever its cost.
+		case status == "UL":
+			up = -math.Min(d[j], 0)
+		default:
+			lo = -math.Max(d[j], 0)
+		}
+
+		lo, up = v.Cost + lo, v.Cost + up
+		if sign < 0 {
+			lo, up = -up, -lo
+		}
+		redCost := 0.0
+		if d[j] != 0 {
+			redCost = sign * d[j]
+		}
+		report.Costs = append(report.Costs, costRange{Name: v.Name, Status: status,
+			Value: v.Value, Cost: sign * v.Cost, RedCost: redCost, Lo: lo, Up: up})
+	}
+
+	// Right-hand side ranges. Moving the bound at which a binding row sits by
+	// delta moves the basic variables by delta times B inverse times e(k).
+	for k, i := range cons {
+		v      := vars[n + k]
+		row    := model.Rows[i]
+		status := boundStatus(v.Value, v.Lo, v.Up)
+		rr     := rhsRange{Name: row.Name, Type: row.Type, Activity: v.Value}
+
+		if isBasic[n + k] {
+			rr.Status = "BS"
+			rr.Lo, rr.Up = math.Inf(-1), math.Inf(1)
+			if !isPlinfy(row.RHSup) {
+				rr.Rhs, rr.Lo = row.RHSup, v.Value
+			} else {
+				rr.Rhs, rr.Up = row.RHSlo, v.Value
+			}
+			report.Rhs = append(report.Rhs, rr)
+			continue
+		}
+
+		rr.Status = status
+		rr.Rhs    = v.Lo
+		if status == "UL" {
+			rr.Rhs = v.Up
+		}
+		rr.Dual = sign * y[k]
+
+		ek := make([]float64, m)
+		ek[k] = 1
+		w := lu.solve(ek)
+		lo, up := math.Inf(-1), math.Inf(1)
+		for p, iVar := range basis {
+			if math.Abs(w[p]) < 1.0e-9 {
+				continue
+			}
+			bv := vars[iVar]
+			if !isNeginf(bv.Lo) {
+				limit := math.Min(bv.Lo - bv.Value, 0) / w[p]
+				if w[p] > 0 {
+					lo = math.Max(lo, limit)
+				} else {
+					up = math.Min(up, limit)
+				}
+			}
+			if !isPlinfy(bv.Up) {
+				limit := math.Max(bv.Up - bv.Value, 0) / w[p]
+				if w[p] > 0 {
+					up = math.Min(up, limit)
+				} else {
+					lo = math.Max(lo, limit)
+				}
+			}
+		}
+		rr.Lo, rr.Up = rr.Rhs + lo, rr.Rhs + up
+		report.Rhs = append(report.Rhs, rr)
+	}
+
+	return report, nil
+}
+
+//==============================================================================
+
+// parseCplexValue converts a value printed by the Cplex interactive optimizer,
+// which may be "zero" or an infinity, to a number. If the string is not a
+// value, the function returns false.
+func parseCplexValue(s string) (float64, bool) {
+
+	switch strings.ToLower(s) {
+	case "zero":
+		return 0, true
+	case "infinity", "+infinity":
+		return math.Inf(1), true
+	case "-infinity":
+		return math.Inf(-1), true
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	return value, err == nil
+}
+
+//==============================================================================
+
+// parseCplexRanges reads the output of the Cplex "display sensitivity obj" and
+// "display sensitivity rhs" commands, and returns for each section the lines
+// listed, keyed by name, as the four values: reduced cost or dual, down,
+// current, and up.
+func parseCplexRanges(output []byte) (map[string][4]float64, map[string][4]float64) {
+	var objRanges = make(map[string][4]float64)  // objective ranges found
+	var rhsRanges = make(map[string][4]float64)  // right-hand side ranges found
+	var current   map[string][4]float64          // section being read
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line   := scanner.Text()
+		fields := strings.Fields(line)
+
+		switch {
+		case strings.Contains(line, "OBJ Sensitivity Ranges"):
+			current = objRanges
+			continue
+		case strings.Contains(line, "RHS Sensitivity Ranges"):
+			current = rhsRanges
+			continue
+		case current == nil || len(fields) != 5:
+			continue
+		}
+
+		var values [4]float64
+		ok := true
+		for i := range values {
+			if values[i], ok = parseCplexValue(fields[i + 1]); !ok {
+				break
+			}
+		}
+		if ok {
+			current[fields[0]] = values
+		}
+	}
+
+	return objRanges, rhsRanges
+}
+
+//==============================================================================
+
+// cplexRanges obtains the ranges of the model from the Cplex interactive
+// optimizer, which solves a copy of the model written with its objective sense
+// and displays its sensitivity ranges. The values of the solution are used for
+// the status and activity of each column and row.
+// In case of failure, function returns an error.
+func cplexRanges(model lpModel, soln lpo.PsSoln, sense string) (rangeReport, error) {
+	var report rangeReport  // ranges obtained
+	var x      []float64    // solution value of each column
+
+	report.Source = "Cplex"
+	report.Sense  = sense
+
+	exe, err := exec.LookPath(cplexExe)
+	if err != nil {
+		return report, errors.Errorf("Cplex interactive optimizer '%s' not found", cplexExe)
+	}
+
+	workDir, err := ioutil.TempDir("", "runopt_range")
+	if err != nil {
+		return report, errors.Wrap(err, "Cannot create work directory")
+	}
+	defer os.RemoveAll(workDir)
+
+	fileName := filepath.Join(workDir, "model.mps")
+	current  := saveModel()
+	saved    := objSense
+	restoreModel(model)
+	objSense = sense
+	err = writeMpsSense(fileName)
+	restoreModel(current)
+	objSense = saved
+	if err != nil {
+		return report, errors.Wrap(err, "Cannot write model for Cplex")
+	}
+
+	cmd := exec.Command(exe, "-c", "read " + fileName, "optimize",
+		"display sensitivity obj -", "display sensitivity rhs -", "quit")
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return report, errors.Wrap(err, "Cplex failed")
+	}
+
+	objRanges, rhsRanges := parseCplexRanges(output)
+	if len(objRanges) == 0 && len(rhsRanges) == 0 {
+		return report, errors.New("Cplex did not display any ranges")
+	}
+
+	x = make([]float64, len(model.Cols))
+	for j, col := range model.Cols {
+		values, ok := objRanges[col.Name]
+		if !ok {
+			return report, errors.Errorf("Cplex gave no range for column '%s'", col.Name)
+		}
+		x[j] = soln.VarMap[col.Name].Value
+		report.Costs = append(report.Costs, costRange{Name: col.Name,
+			Status: boundStatus(x[j], col.BndLo, col.BndUp), Value: x[j],
+			Cost: values[2], RedCost: values[0], Lo: values[1], Up: values[3]})
+	}
+
+	for i, row := range model.Rows {
+		if isFreeRow(row) {
+			continue
+		}
+		values, ok := rhsRanges[row.Name]
+		if !ok {
+			return report, errors.Errorf("Cplex gave no range for row '%s'", row.Name)
+		}
+		activity := rowActivity(model, i, x)
+		report.Rhs = append(report.Rhs, rhsRange{Name: row.Name, Type: row.Type,
+			Status: boundStatus(activity, row.RHSlo, row.RHSup), Activity: activity,
+			Rhs: values[2], Dual: values[0], Lo: values[1], Up: values[3]})
+	}
+
+	return report, nil
+}
+
+//==============================================================================
+
+// printRanges prints the ranges, pausing periodically so output does not
+// scroll off the screen. The function returns no values.
+func printRanges(report rangeReport) {
+	var userString string  // user input
+	var counter    int     // number of lines printed since last pause
+
+	pause := func() bool {
+		counter++
+		if counter < pauseAfter {
+			return false
+		}
+		counter    = 0
+		userString = ""
+		fmt.Printf("\nPAUSED... <CR> continue, any key to quit: ")
+		fmt.Scanln(&userString)
+		return userString != ""
+	}
+
+	fmt.Printf("\nRanges %s, objective sense %s.\n", report.Source, report.Sense)
+	for _, warning := range report.Warnings {
+		fmt.Printf("WARNING: %s\n", warning)
+	}
+
+	fmt.Printf("\nObjective coefficient ranges:\n")
+	fmt.Printf("%-16s %3s %15s %15s %15s %15s %15s\n", "COLUMN", "ST", "VALUE", "COST",
+		"REDUCED COST", "LOWER", "UPPER")
+	for _, c := range report.Costs {
+		fmt.Printf("%-16s %3s %15e %15e %15e %15e %15e\n", c.Name, c.Status, c.Value,
+			c.Cost, c.RedCost, c.Lo, c.Up)
+		if pause() {
+			break
+		}
+	}
+
+	counter = 0
+	fmt.Printf("\nRight-hand side ranges:\n")
+	fmt.Printf("%-16s %2s %3s %15s %15s %15s %15s %15s\n", "ROW", "EQ", "ST", "ACTIVITY",
+		"RHS", "DUAL", "LOWER", "UPPER")
+	for _, r := range report.Rhs {
+		fmt.Printf("%-16s %2s %3s %15e %15e %15e %15e %15e\n", r.Name, r.Type, r.Status,
+			r.Activity, r.Rhs, r.Dual, r.Lo, r.Up)
+		if pause() {
+			break
+		}
+	}
+
+}
+
+//==============================================================================
+
+// writeRangesCsv writes the ranges to a CSV file, one line per column or row.
+// In case of failure, function returns an error.
+func writeRangesCsv(fileName string, report rangeReport) error {
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create file %s", fileName)
+	}
+	defer f.Close()
+
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"kind", "name", "type", "status", "value", "current", "reduced_cost_or_dual",
+		"lower", "upper"})
+	for _, c := range report.Costs {
+		w.Write([]string{"cost", c.Name, "", c.Status, format(c.Value), format(c.Cost),
+			format(c.RedCost), format(c.Lo), format(c.Up)})
+	}
+	for _, r := range report.Rhs {
+		w.Write([]string{"rhs", r.Name, r.Type, r.Status, format(r.Activity), format(r.Rhs),
+			format(r.Dual), format(r.Lo), format(r.Up)})
+	}
+
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return errors.Wrapf(err, "Failed to write file %s", fileName)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// wpRanging reports the sensitivity ranges of the current lpo solution. The
+// model is read from the MPS file given or, if none is given, taken from the
+// lpo data structures, and must be an LP. The ranges are obtained from Cplex
+// when its interactive optimizer is available, and computed from the optimal
+// basis otherwise, or when "basis" is given in any position on the command
+// line. A file name of "-" stands for the data structures.
+// In case of failure, function returns an error.
+func wpRanging() error {
+	var fileName string       // MPS file holding the model
+	var fileCsv  string       // CSV output file
+	var model    lpModel      // model ranged
+	var report   rangeReport  // ranges obtained
+	var err      error        // error returned by functions called
+
+	if len(psResult.VarMap) == 0 {
+		return errors.New("wpRanging failed, no solution available")
+	}
+
+	// The basis flag may be given before or after the file name.
+	useBasis := false
+	var args []string  // arguments other than the basis flag
+	for _, arg := range cmdArgs {
+		if strings.ToLower(arg) == "basis" {
+			useBasis = true
+		} else {
+			args = append(args, arg)
+		}
+	}
+	cmdArgs = args
+
+	fileName = promptArg(0, "Enter original MPS file name or <CR> to use data structures: ")
+	if fileName == "-" {
+		fileName = ""
+	}
+	if fileName != "" {
+		if custEnvOn {
+			fileName = dSrcDev + fileName + fExtension
+		}
+		if model, err = readModelFile(fileName); err != nil {
+			return errors.Wrap(err, "wpRanging failed")
+		}
+	} else {
+		model = saveModel()
+	}
+
+	for _, col := range model.Cols {
+		if isIntCol(col) {
+			return errors.Errorf("wpRanging failed, column '%s' is integer, ranges are for LPs only", col.Name)
+		}
+	}
+
+	err = errors.New("basis requested")
+	if !useBasis {
+		report, err = cplexRanges(model, psResult, objSense)
+	}
+	if err != nil {
+		fmt.Printf("Ranges not obtained from Cplex (%s), computing them from the basis.\n",
+			firstLine(err.Error()))
+		if report, err = computeRanges(model, psResult, objSense); err != nil {
+			return errors.Wrap(err, "wpRanging failed")
+		}
+	}
+
+	printRanges(report)
+
+	fmt.Printf("\nEnter CSV file name for the ranges or <CR> for none: ")
+	fmt.Scanln(&fileCsv)
+	if fileCsv != "" {
+		if custEnvOn {
+			fileCsv = dSrcDev + fileCsv + ".csv"
+		}
+		if err = writeRangesCsv(fileCsv, report); err != nil {
+			return errors.Wrap(err, "wpRanging failed")
+		}
+		fmt.Printf("Ranges written to '%s'.\n", fileCsv)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// This file contains the writing of the reduced matrix to an MPS file, with
+// comments recording the source of the model and the reductions applied.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//==============================================================================
+
+// rdcdHeader returns the comment lines placed at the top of a reduced MPS file:
+// the source of the model, the reductions applied, the numbers of rows, columns
+// and elements they removed, the objective sense, and the time of writing.
+func rdcdHeader(source string, psCtrl lpo.PsCtrl, rowsDel, colsDel, elemDel int) []string {
+	var flags []string  // names of the reductions applied
+
+	if psCtrl.DelRowNonbinding {
+		flags = append(flags, "DelRowNonbinding")
+	}
+	if psCtrl.DelRowSingleton {
+		flags = append(flags, "DelRowSingleton")
+	}
+	if psCtrl.DelColSingleton {
+		flags = append(flags, "DelColSingleton")
+	}
+	if psCtrl.DelFixedVars {
+		flags = append(flags, "DelFixedVars")
+	}
+	if len(flags) == 0 {
+		flags = append(flags, "none")
+	}
+
+	return []string{
+		"* Reduced matrix written by runopt",
+		"* Source:     " + source,
+		fmt.Sprintf("* Reductions: %s (maxiter %d)", strings.Join(flags, " "), psCtrl.MaxIter),
+		fmt.Sprintf("* Removed:    %d rows, %d cols, %d elements", rowsDel, colsDel, elemDel),
+		"* Sense:      " + objSense,
+		"* Written:    " + time.Now().Format("2006-01-02 15:04:05"),
+	}
+}
+
+//==============================================================================
+
+// negateField returns the line with the sign of its numeric field k, counted
+// from 0, changed. The digits are kept as written, and the spaces after the
+// field are adjusted so that the following fields keep their columns.
+func negateField(line string, k int) string {
+	var start, end int  // position of the field in the line
+
+	for field := 0; ; field++ {
+		for start = end; start < len(line) && strings.IndexByte(" \t\r", line[start]) >= 0; start++ {
+		}
+		for end = start; end < len(line) && strings.IndexByte(" \t\r", line[end]) < 0; end++ {
+		}
+		if start == len(line) {
+			return line
+		}
+		if field == k {
+			break
+		}
+	}
+
+	old := line[start:end]
+	if value, err := strconv.ParseFloat(old, 64); err != nil || value == 0 {
+		return line
+	}
+
+	var value string  // field with its sign changed
+	switch old[0] {
+	case '-':
+		value = old[1:]
+	case '+':
+		value = "-" + old[1:]
+	default:
+		value = "-" + old
+	}
+
+	rest := line[end:]
+	if rest != "" {
+		if len(value) < len(old) {
+			rest = " " + rest
+		} else if len(value) > len(old) && strings.HasPrefix(rest, "  ") {
+			rest = rest[1:]
+		}
+	}
+
+	return line[:start] + value + rest
+}
+
+//==============================================================================
+
+// negateMpsObjective changes the sign of the objective coefficients, and of the
+// objective constant given in the RHS section, in the lines of an MPS file. The
+// objective is the first row of type N.
+func negateMpsObjective(lines []string) {
+	var objName string  // name of the objective row
+	var section string  // section being read
+
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(line, "*") {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			section = strings.ToUpper(fields[0])
+			continue
+		}
+
+		switch section {
+		case "ROWS":
+			if objName == "" && len(fields) == 2 && strings.ToUpper(fields[0]) == "N" {
+				objName = fields[1]
+			}
+
+		case "COLUMNS", "RHS":
+			if len(fields) > 1 && strings.Contains(fields[1], "MARKER") {
+				continue
+			}
+			for k := len(fields) % 2; k + 1 < len(fields); k += 2 {
+				if fields[k] == objName {
+					lines[i] = negateField(lines[i], k + 1)
+				}
+			}
+		}
+	}
+
+}
+
+//==============================================================================
+
+// stampReducedMps inserts the header comments at the top of a reduced MPS file.
+// If the file was written by lpo during the solve of a model to be maximized,
+// negated is set, and the objective, which lpo reduced as the minimization of
+// its negation, is changed back and given an OBJSENSE section.
+// In case of failure, function returns an error.
+func stampReducedMps(fileName string, header []string, negated bool) error {
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot read reduced MPS file %s", fileName)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if negated {
+		negateMpsObjective(lines)
+	}
+	lines = append(header, lines...)
+
+	if err = ioutil.WriteFile(fileName, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return errors.Wrapf(err, "Cannot write reduced MPS file %s", fileName)
+	}
+
+	if negated {
+		return copyMpsSense(fileName, fileName, nil, senseMax)
+	}
+
+	return nil
+}
@@ -0,0 +1,304 @@
+// This file contains functions controlling how solves are run: interrupt
+// handling, wall-clock limits, and clean-up of solver subprocesses.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors returned when a solve does not run to completion.
+
+var errInterrupted = errors.New("solve interrupted by user")
+var errTimeLimit   = errors.New("solve time limit exceeded")
+
+// Time allowed for an aborted solve to return after its subprocesses are killed.
+
+const abortGrace = 10 * time.Second
+
+// State shared between the signal handler and the solve in progress. If
+// solveCancel is nil, no solve is running and an interrupt terminates the program.
+
+var solveMutex   sync.Mutex          // guards solveCancel
+var solveCancel  context.CancelFunc  // cancels the solve in progress
+var pendingSolve chan error          // aborted solve which has not yet returned
+
+//==============================================================================
+
+// initSignals installs the handler for Ctrl-C. An interrupt received while a
+// solve is running cancels only that solve, while an interrupt received at any
+// other time terminates the program cleanly. The function accepts no arguments
+// and returns no values.
+func initSignals() {
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+
+	go func() {
+		for range sigChan {
+			solveMutex.Lock()
+			cancel := solveCancel
+			solveMutex.Unlock()
+
+			if cancel != nil {
+				fmt.Printf("\nInterrupt received, aborting solve...\n")
+				cancel()
+				continue
+			}
+
+			fmt.Printf("\n\nInterrupt received.")
+			wpShutdown()
+			os.Exit(0)
+		}
+	}()
+
+}
+
+//==============================================================================
+
+// beginSolve creates the context under which a solve runs, and registers it so
+// that an interrupt cancels the solve instead of terminating the program. If
+// timeLimit is greater than zero, the context expires after that much time.
+// The function returns the context and a function which must be called once the
+// solve is over.
+func beginSolve(timeLimit time.Duration) (context.Context, func()) {
+	var ctx      context.Context     // context controlling the solve
+	var cancel   context.CancelFunc  // function cancelling the context
+
+	if timeLimit > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeLimit)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+
+	solveMutex.Lock()
+	solveCancel = cancel
+	solveMutex.Unlock()
+
+	endSolve := func() {
+		solveMutex.Lock()
+		solveCancel = nil
+		solveMutex.Unlock()
+		cancel()
+	}
+
+	return ctx, endSolve
+}
+
+//==============================================================================
+
+// abortError returns the error reported for a solve whose context is done,
+// distinguishing between an interrupt and an expired time limit.
+func abortError(ctx context.Context) error {
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return errTimeLimit
+	}
+	return errInterrupted
+}
+
+//==============================================================================
+
+// solveWithCancel runs the solver selected by useCoin under a cancellable
+// context. If timeLimit is greater than zero, the solve is aborted once that
+// much wall-clock time has elapsed. When the solve is aborted by an interrupt or
+// by the time limit, any solver subprocesses are killed and the caller receives
+// errInterrupted or errTimeLimit. The result is copied to psSoln only if the
+// solve completed. In case of failure, function returns an error.
+func solveWithCancel(timeLimit time.Duration, useCoin bool, psCtrl lpo.PsCtrl,
+	psSoln *lpo.PsSoln) error {
+
+	ctx, endSolve := beginSolve(timeLimit)
+	defer endSolve()
+
+	return solveInContext(ctx, useCoin, psCtrl, psSoln)
+}
+
+//==============================================================================
+
+// solveInContext runs the solver selected by useCoin until it returns or the
+// context is done, for callers which run several solves under one context
+// created by beginSolve. It behaves as described for solveWithCancel.
+// In case of failure, function returns an error.
+func solveInContext(ctx context.Context, useCoin bool, psCtrl lpo.PsCtrl,
+	psSoln *lpo.PsSoln) error {
+	var result   lpo.PsSoln          // result filled in by the solver goroutine
+
+	// An aborted solve may still be unwinding inside lpo, and lpo state is
+	// global, so do not start another one until it has returned.
+	if err := checkPendingSolve(); err != nil {
+		return err
+	}
+
+	if ctx.Err() != nil {
+		return abortError(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- callSolver(useCoin, psCtrl, &result)
+	}()
+
+	select {
+
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+		*psSoln = result
+		return nil
+
+	case <-ctx.Done():
+		killSolverProcs()
+
+		select {
+		case <-done:
+			// Solve returned after its subprocesses were killed.
+		case <-time.After(abortGrace):
+			fmt.Printf("WARNING: Aborted solve has not returned, it will finish in background.\n")
+			pendingSolve = done
+		}
+
+		return abortError(ctx)
+
+	} // end select on solve completion
+
+}
+
+//==============================================================================
+
+// checkPendingSolve determines whether a solve aborted earlier is still running.
+// The function accepts no arguments. If the solve has not returned, function
+// returns an error.
+func checkPendingSolve() error {
+
+	if pendingSolve == nil {
+		return nil
+	}
+
+	select {
+	case <-pendingSolve:
+		pendingSolve = nil
+		return nil
+	default:
+		return errors.New("Previous aborted solve is still running, try again later")
+	}
+
+}
+
+//==============================================================================
+
+// killSolverProcs kills all processes descended from this one, which are the
+// solver executables started by lpo. Processes are found by scanning /proc, so
+// on platforms without it nothing is killed; on those platforms the solver still
+// receives the interrupt directly from the terminal. The function accepts no
+// arguments and returns no values.
+func killSolverProcs() {
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	for _, pid := range childProcs(os.Getpid()) {
+		if proc, err := os.FindProcess(pid); err == nil {
+			if err = proc.Kill(); err == nil {
+				fmt.Printf("Killed solver process %d.\n", pid)
+			}
+		}
+	}
+
+}
+
+//==============================================================================
+
+// killProcTree kills the process specified together with all of its
+// descendants, which is needed for worker processes since killing a worker
+// alone would leave its solver running. Descendants are found as described
+// for killSolverProcs. The function returns no values.
+func killProcTree(proc *os.Process) {
+
+	if runtime.GOOS == "linux" {
+		for _, pid := range childProcs(proc.Pid) {
+			if child, err := os.FindProcess(pid); err == nil {
+				_ = child.Kill()
+			}
+		}
+	}
+
+	_ = proc.Kill()
+}
+
+//==============================================================================
+
+// childProcs returns the process ids of all descendants of the process
+// specified, children before grandchildren. Processes which cannot be read are
+// skipped.
+func childProcs(parent int) []int {
+	var children []int  // list of descendants found
+
+	entries, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		stat, err := ioutil.ReadFile(filepath.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+
+		// The command name in field 2 may contain spaces, so fields are counted
+		// from the closing parenthesis; the parent id is the second field after it.
+		fields := strings.Fields(string(stat[strings.LastIndex(string(stat), ")")+1:]))
+		if len(fields) < 2 {
+			continue
+		}
+
+		if ppid, err := strconv.Atoi(fields[1]); err == nil && ppid == parent {
+			children = append(children, pid)
+			children = append(children, childProcs(pid)...)
+		}
+	}
+
+	return children
+}
+
+//==============================================================================
+
+// wpGetTimeLimit prompts the user for an optional wall-clock limit on a solve.
+// It returns the limit, which is zero if the user did not enter one.
+func wpGetTimeLimit() time.Duration {
+	var userString string   // input provided by user
+
+	fmt.Printf("Enter solve time limit in seconds or <CR> for none: ")
+	fmt.Scanln(&userString)
+	if userString == "" {
+		return 0
+	}
+
+	seconds, err := strconv.ParseFloat(userString, 64)
+	if err != nil || seconds <= 0 {
+		fmt.Printf("'%s' is not a valid time limit, none will be used.\n", userString)
+		return 0
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
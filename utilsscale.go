@@ -0,0 +1,532 @@
+// This file contains the scaling of the model held in the lpo data structures
+// by rows and columns, with a choice of methods, and the diagnostics showing
+// the range of the coefficients before and after scaling.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Number of rows and columns with the widest coefficient ranges listed.
+
+const scaleWorst = 10
+
+// Smallest relative reduction of the overall coefficient range for which the
+// iterated method makes another pass.
+
+const scaleMinGain = 0.1
+
+// coefRange holds the smallest and largest absolute value of the non-zero
+// coefficients of a row or column, outside the objective and other free rows.
+type coefRange struct {
+	Name  string   // row or column name
+	Count int      // non-zero coefficients
+	Min   float64  // smallest absolute value
+	Max   float64  // largest absolute value
+}
+
+// scaling holds the scaling applied to the model held in the lpo data
+// structures. Row i is multiplied by RowScale[i], and column j by ColScale[j],
+// so that the variable of the scaled model is x[j] / ColScale[j].
+type scaling struct {
+	Method   string     // methods applied, in order, empty if none
+	Model    lpModel    // model before scaling
+	Scaled   lpModel    // model as left by the last scaling
+	RowScale []float64  // factor of each row
+	ColScale []float64  // factor of each column
+}
+
+// Scaling applied to the model held in the lpo data structures.
+
+var modelScale scaling
+
+//==============================================================================
+
+// rangeRatio returns the ratio of the largest to the smallest coefficient of a
+// range, or 1 if the range holds no coefficients.
+func rangeRatio(r coefRange) float64 {
+
+	if r.Count == 0 || r.Min == 0 {
+		return 1
+	}
+
+	return r.Max / r.Min
+}
+
+//==============================================================================
+
+// unitFactors returns n scale factors of 1, which leave a model unchanged.
+func unitFactors(n int) []float64 {
+
+	factors := make([]float64, n)
+	for k := range factors {
+		factors[k] = 1
+	}
+
+	return factors
+}
+
+//==============================================================================
+
+// isScaledRow determines whether the row of the model takes part in scaling,
+// which leaves out the objective and other free rows.
+func isScaledRow(model lpModel, iRow int) bool {
+
+	return iRow != model.ObjRow && !isFreeRow(model.Rows[iRow])
+}
+
+//==============================================================================
+
+// coefRanges returns the range of the coefficients of each row and column of
+// the model once scaled by the factors given, indexed by row and column.
+func coefRanges(model lpModel, rowScale, colScale []float64) ([]coefRange, []coefRange) {
+
+	rows := make([]coefRange, len(model.Rows))
+	cols := make([]coefRange, len(model.Cols))
+	for i, row := range model.Rows {
+		rows[i].Name = row.Name
+	}
+	for j, col := range model.Cols {
+		cols[j].Name = col.Name
+	}
+
+	add := func(r *coefRange, value float64) {
+		if r.Count == 0 || value < r.Min {
+			r.Min = value
+		}
+		if r.Count == 0 || value > r.Max {
+			r.Max = value
+		}
+		r.Count++
+	}
+
+	for _, elem := range model.Elems {
+		if elem.Value == 0 || !isScaledRow(model, elem.InRow) {
+			continue
+		}
+		value := math.Abs(elem.Value) * rowScale[elem.InRow] * colScale[elem.InCol]
+		add(&rows[elem.InRow], value)
+		add(&cols[elem.InCol], value)
+	}
+
+	return rows, cols
+}
+
+//==============================================================================
+
+// overallRange returns the range of all the coefficients of the rows given.
+func overallRange(rows []coefRange) coefRange {
+	var all coefRange  // range over all rows
+
+	for _, r := range rows {
+		if r.Count == 0 {
+			continue
+		}
+		if all.Count == 0 || r.Min < all.Min {
+			all.Min = r.Min
+		}
+		if all.Count == 0 || r.Max > all.Max {
+			all.Max = r.Max
+		}
+		all.Count += r.Count
+	}
+
+	return all
+}
+
+//==============================================================================
+
+// scalePass makes one pass of the method given, "geom" or "equil", over the
+// rows and then the columns of the model, multiplying the factors given by the
+// factors found. The geometric method divides each row or column by the square
+// root of the product of its smallest and largest coefficient, the
+// equilibration method by its largest coefficient. Integer columns are not
+// scaled, so that their values stay integer.
+func scalePass(model lpModel, method string, rowScale, colScale []float64) {
+
+	factor := func(r coefRange) float64 {
+		if r.Count == 0 {
+			return 1
+		}
+		if method == "equil" {
+			return 1 / r.Max
+		}
+		return 1 / math.Sqrt(r.Min * r.Max)
+	}
+
+	rows, _ := coefRanges(model, rowScale, colScale)
+	for i := range rowScale {
+		if isScaledRow(model, i) {
+			rowScale[i] *= factor(rows[i])
+		}
+	}
+
+	_, cols := coefRanges(model, rowScale, colScale)
+	for j := range colScale {
+		if !isIntCol(model.Cols[j]) {
+			colScale[j] *= factor(cols[j])
+		}
+	}
+
+}
+
+//==============================================================================
+
+// scaleFactors returns the row and column factors found for the model by the
+// method given: "geom" or "equil" for one pass of either method, or "iter" for
+// geometric passes repeated while they reduce the overall range by at least
+// scaleMinGain, up to maxPass, followed by one equilibration pass. If pow2 is
+// set, each factor is rounded to the nearest power of two, so that scaling adds
+// no rounding error to the coefficients.
+func scaleFactors(model lpModel, method string, maxPass int, pow2 bool) ([]float64, []float64) {
+
+	rowScale := unitFactors(len(model.Rows))
+	colScale := unitFactors(len(model.Cols))
+
+	switch method {
+	case "iter":
+		rows, _ := coefRanges(model, rowScale, colScale)
+		ratio   := rangeRatio(overallRange(rows))
+		for pass := 0; pass < maxPass; pass++ {
+			scalePass(model, "geom", rowScale, colScale)
+			rows, _ = coefRanges(model, rowScale, colScale)
+			newRatio := rangeRatio(overallRange(rows))
+			if newRatio > ratio * (1 - scaleMinGain) {
+				break
+			}
+			ratio = newRatio
+		}
+		scalePass(model, "equil", rowScale, colScale)
+
+	default:
+		scalePass(model, method, rowScale, colScale)
+	}
+
+	if pow2 {
+		for _, factors := range [][]float64{rowScale, colScale} {
+			for k, f := range factors {
+				factors[k] = math.Exp2(math.Round(math.Log2(f)))
+			}
+		}
+	}
+
+	return rowScale, colScale
+}
+
+//==============================================================================
+
+// scaleModel returns a copy of the model scaled by the factors given. Row
+// limits are multiplied by the row factor, column bounds divided by the column
+// factor, and infinite values are left as they are.
+func scaleModel(model lpModel, rowScale, colScale []float64) lpModel {
+
+	work := copyModel(model)
+	for k, elem := range work.Elems {
+		work.Elems[k].Value = elem.Value * rowScale[elem.InRow] * colScale[elem.InCol]
+	}
+
+	for i := range work.Rows {
+		row := &work.Rows[i]
+		if !isNeginf(row.RHSlo) {
+			row.RHSlo *= rowScale[i]
+		}
+		if !isPlinfy(row.RHSup) {
+			row.RHSup *= rowScale[i]
+		}
+	}
+
+	for j := range work.Cols {
+		col := &work.Cols[j]
+		if !isNeginf(col.BndLo) {
+			col.BndLo /= colScale[j]
+		}
+		if !isPlinfy(col.BndUp) {
+			col.BndUp /= colScale[j]
+		}
+	}
+
+	return work
+}
+
+//==============================================================================
+
+// unscaleSoln converts a solution of the scaled model into a solution of the
+// model before scaling. The objective value is not changed, since the objective
+// row is not scaled. The function returns no values.
+func unscaleSoln(soln *lpo.PsSoln, model lpModel, rowScale, colScale []float64) {
+
+	for j, col := range model.Cols {
+		if varb, ok := soln.VarMap[col.Name]; ok {
+			varb.Value       *= colScale[j]
+			varb.ReducedCost /= colScale[j]
+			soln.VarMap[col.Name] = varb
+		}
+	}
+
+	for i, row := range model.Rows {
+		if con, ok := soln.ConMap[row.Name]; ok {
+			con.Rhs   /= rowScale[i]
+			con.Slack /= rowScale[i]
+			con.Pi    *= rowScale[i]
+			con.Dual  *= rowScale[i]
+			soln.ConMap[row.Name] = con
+		}
+	}
+
+}
+
+//==============================================================================
+
+// worstRanges returns the indexes of the rows or columns with the widest
+// coefficient ranges, widest first, at most scaleWorst of them.
+func worstRanges(ranges []coefRange) []int {
+	var indexes []int  // rows or columns with coefficients
+
+	for k, r := range ranges {
+		if r.Count > 0 {
+			indexes = append(indexes, k)
+		}
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		return rangeRatio(ranges[indexes[a]]) > rangeRatio(ranges[indexes[b]])
+	})
+
+	if len(indexes) > scaleWorst {
+		indexes = indexes[:scaleWorst]
+	}
+
+	return indexes
+}
+
+//==============================================================================
+
+// printCoefRanges prints the overall coefficient range of the model before and
+// after scaling, and the rows and columns with the widest ranges after it,
+// with their ranges before. If the model is not being scaled, after is nil and
+// only the current ranges are printed. The function returns no values.
+func printCoefRanges(beforeRows, beforeCols, afterRows, afterCols []coefRange) {
+
+	worst := func(ranges []coefRange) float64 {
+		var ratio float64 = 1  // widest range found
+		for _, r := range ranges {
+			ratio = math.Max(ratio, rangeRatio(r))
+		}
+		return ratio
+	}
+
+	before := overallRange(beforeRows)
+	if afterRows == nil {
+		fmt.Printf("\n%-24s %12s\n", "", "Current")
+		fmt.Printf("%-24s %12.4g\n", "Smallest |a_ij|", before.Min)
+		fmt.Printf("%-24s %12.4g\n", "Largest |a_ij|", before.Max)
+		fmt.Printf("%-24s %12.4g\n", "Overall range", rangeRatio(before))
+		fmt.Printf("%-24s %12.4g\n", "Widest row range", worst(beforeRows))
+		fmt.Printf("%-24s %12.4g\n", "Widest column range", worst(beforeCols))
+		afterRows, afterCols = beforeRows, beforeCols
+	} else {
+		after := overallRange(afterRows)
+		fmt.Printf("\n%-24s %12s %12s\n", "", "Before", "After")
+		fmt.Printf("%-24s %12.4g %12.4g\n", "Smallest |a_ij|", before.Min, after.Min)
+		fmt.Printf("%-24s %12.4g %12.4g\n", "Largest |a_ij|", before.Max, after.Max)
+		fmt.Printf("%-24s %12.4g %12.4g\n", "Overall range", rangeRatio(before), rangeRatio(after))
+		fmt.Printf("%-24s %12.4g %12.4g\n", "Widest row range", worst(beforeRows), worst(afterRows))
+		fmt.Printf("%-24s %12.4g %12.4g\n", "Widest column range", worst(beforeCols), worst(afterCols))
+	}
+
+	for _, kind := range []string{"Rows", "Columns"} {
+		before, after := beforeRows, afterRows
+		if kind == "Columns" {
+			before, after = beforeCols, afterCols
+		}
+		fmt.Printf("\n%s with the widest ranges:\n", kind)
+		fmt.Printf("  %-16s %6s %12s %12s %12s %12s\n", "Name", "Count", "Min", "Max", "Range", "Before")
+		for _, k := range worstRanges(after) {
+			fmt.Printf("  %-16s %6d %12.4g %12.4g %12.4g %12.4g\n", after[k].Name, after[k].Count,
+				after[k].Min, after[k].Max, rangeRatio(after[k]), rangeRatio(before[k]))
+		}
+	}
+
+}
+
+//==============================================================================
+
+// writeScaleCsv writes the coefficient range of every row and column before
+// and after scaling, and its scale factor, to a CSV file.
+// In case of failure, function returns an error.
+func writeScaleCsv(fileName string, beforeRows, beforeCols, afterRows, afterCols []coefRange,
+	rowScale, colScale []float64) error {
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot create file %s", fileName)
+	}
+	defer f.Close()
+
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'g', 6, 64)
+	}
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"kind", "name", "count", "min_before", "max_before", "range_before",
+		"min_after", "max_after", "range_after", "factor"})
+	for _, kind := range []string{"row", "col"} {
+		before, after, factors := beforeRows, afterRows, rowScale
+		if kind == "col" {
+			before, after, factors = beforeCols, afterCols, colScale
+		}
+		for k := range before {
+			w.Write([]string{kind, before[k].Name, strconv.Itoa(before[k].Count),
+				format(before[k].Min), format(before[k].Max), format(rangeRatio(before[k])),
+				format(after[k].Min), format(after[k].Max), format(rangeRatio(after[k])),
+				format(factors[k])})
+		}
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+//==============================================================================
+
+// wpScaleUndo restores the model held in the lpo data structures as it was
+// before scaling, and converts the lpo solution and the solution pool, if there
+// are any, to it. If the model was changed after it was scaled, the changes
+// would be lost, so the user is asked to confirm first.
+// In case of failure, function returns an error.
+func wpScaleUndo() error {
+	var userString string  // input provided by user
+
+	if modelScale.Method == "" {
+		return errors.New("wpScaleUndo failed, the model is not scaled")
+	}
+	if !reflect.DeepEqual(saveModel(), modelScale.Scaled) {
+		fmt.Printf("WARNING: the model has changed since it was scaled. Undo restores the model\n")
+		fmt.Printf("as it was before scaling, and these changes are lost.\n")
+		fmt.Printf("Undo the scaling anyway [Y|N]: ")
+		fmt.Scanln(&userString)
+		if userString != "y" && userString != "Y" {
+			return errors.New("wpScaleUndo cancelled, the model is left unchanged")
+		}
+	}
+
+	scaled := modelScale.Scaled
+	if len(psResult.VarMap) > 0 {
+		unscaleSoln(&psResult, scaled, modelScale.RowScale, modelScale.ColScale)
+		fmt.Printf("The lpo solution was converted to the unscaled model.\n")
+	}
+	for k := range psPool {
+		unscaleSoln(&psPool[k], scaled, modelScale.RowScale, modelScale.ColScale)
+	}
+	restoreModel(modelScale.Model)
+	fmt.Printf("Scaling '%s' removed, the model is restored.\n", modelScale.Method)
+	modelScale = scaling{}
+
+	return nil
+}
+
+//==============================================================================
+
+// wpScale scales the model held in the lpo data structures by the method given
+// on the command line as "scale geom|equil|iter [pow2]", or entered at the
+// prompt, printing the range of the coefficients before and after. The scaling
+// is applied once confirmed, and combined with any scaling applied earlier.
+// "scale report" prints the current ranges, and "scale undo" restores the model
+// as it was before scaling. In case of failure, function returns an error.
+func wpScale() error {
+	var userString string  // input provided by user
+	var fileCsv    string  // CSV output file
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+		return errors.New("wpScale failed, model not defined")
+	}
+
+	method := strings.ToLower(promptArg(0, "Enter scaling method [geom|equil|iter|report|undo]: "))
+	pow2   := len(cmdArgs) > 1 && strings.ToLower(cmdArgs[1]) == "pow2"
+	if len(cmdArgs) < 1 && method != "report" && method != "undo" {
+		fmt.Printf("Round factors to powers of two [Y|N]: ")
+		fmt.Scanln(&userString)
+		pow2 = userString == "y" || userString == "Y"
+	}
+
+	model := saveModel()
+	beforeRows, beforeCols := coefRanges(model, unitFactors(len(model.Rows)), unitFactors(len(model.Cols)))
+
+	switch method {
+	case "report":
+		if modelScale.Method != "" {
+			fmt.Printf("The model is scaled by '%s'.\n", modelScale.Method)
+		}
+		printCoefRanges(beforeRows, beforeCols, nil, nil)
+		return nil
+
+	case "undo":
+		return wpScaleUndo()
+
+	case "geom", "equil", "iter":
+		// Handled below
+
+	default:
+		return errors.Errorf("wpScale failed, unknown method '%s'", method)
+	}
+
+	rowScale, colScale := scaleFactors(model, method, paramMaxIter(20), pow2)
+	afterRows, afterCols := coefRanges(model, rowScale, colScale)
+	printCoefRanges(beforeRows, beforeCols, afterRows, afterCols)
+
+	fmt.Printf("\nEnter CSV file name for the ranges of all rows and columns or <CR> for none: ")
+	fmt.Scanln(&fileCsv)
+	if fileCsv != "" {
+		if custEnvOn {
+			fileCsv = dSrcDev + fileCsv + ".csv"
+		}
+		if err := writeScaleCsv(fileCsv, beforeRows, beforeCols, afterRows, afterCols,
+			rowScale, colScale); err != nil {
+			return errors.Wrap(err, "wpScale failed")
+		}
+		fmt.Printf("Ranges written to '%s'.\n", fileCsv)
+	}
+
+	userString = ""
+	fmt.Printf("\nApply the scaling to the model [Y|N]: ")
+	fmt.Scanln(&userString)
+	if userString != "y" && userString != "Y" {
+		fmt.Printf("Model left unchanged.\n")
+		return nil
+	}
+
+	// A model scaled earlier keeps its original, with the factors combined.
+	if modelScale.Method == "" {
+		modelScale = scaling{Model: model, RowScale: unitFactors(len(model.Rows)), ColScale: unitFactors(len(model.Cols))}
+	} else {
+		modelScale.Method += "+"
+	}
+	modelScale.Method += method
+	if pow2 {
+		modelScale.Method += "/pow2"
+	}
+	for i := range rowScale {
+		modelScale.RowScale[i] *= rowScale[i]
+	}
+	for j := range colScale {
+		modelScale.ColScale[j] *= colScale[j]
+	}
+
+	restoreModel(scaleModel(model, rowScale, colScale))
+	modelScale.Scaled = saveModel()
+	clearLpoSoln()
+	fmt.Printf("Model scaled by '%s'; solutions are in scaled units until \"scale undo\".\n",
+		modelScale.Method)
+
+	return nil
+}
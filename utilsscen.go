@@ -0,0 +1,563 @@
+// This file contains the what-if scenarios, which apply named edits read from a
+// scenario file to a copy of the model, and compare the solution of each with
+// the solution of the base model.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Number of variables compared when none are chosen by the user: those whose
+// values change most from the base solution.
+
+const scenMaxVarbs = 10
+
+// scenEdit is one edit of a scenario, as read from the scenario file.
+type scenEdit struct {
+	Line  int      // line of the scenario file
+	Op    string   // bound, rhs, coef, delrow or delcol
+	Row   string   // row edited
+	Col   string   // column edited
+	Which string   // bound set: lo, up, fix or free
+	Value float64  // new value
+}
+
+// scenario is a named set of edits applied together to the base model.
+type scenario struct {
+	Name  string      // scenario name
+	Edits []scenEdit  // edits, applied in order
+}
+
+// scenResult holds the solution of one model compared.
+type scenResult struct {
+	Name   string      // scenario name, or "BASE"
+	Status string      // "optimal", or the reason no solution was obtained
+	Model  lpModel     // model solved
+	Soln   lpo.PsSoln  // solution obtained
+}
+
+//==============================================================================
+
+// parseScenValue returns the value of a scenario edit, accepting "inf" and
+// "-inf" for infinite bounds and limits. In case of failure, function returns
+// an error.
+func parseScenValue(field string) (float64, error) {
+
+	value, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0, errors.Errorf("'%s' is not a valid number", field)
+	}
+
+	switch {
+	case isPlinfy(value):
+		value = lpo.Plinfy
+	case isNeginf(value):
+		value = lpo.Neginf
+	}
+
+	return value, nil
+}
+
+//==============================================================================
+
+// parseScenEdit returns the edit described by the fields of a line of the
+// scenario file. In case of failure, function returns an error.
+func parseScenEdit(fields []string) (scenEdit, error) {
+	var edit scenEdit  // edit read
+	var err  error     // error returned by functions called
+
+	edit.Op = strings.ToLower(fields[0])
+	switch edit.Op {
+	case "bound":
+		if len(fields) < 3 {
+			return edit, errors.New("expected 'bound column lo|up|fix|free [value]'")
+		}
+		edit.Col, edit.Which = fields[1], strings.ToLower(fields[2])
+		switch {
+		case edit.Which == "free" && len(fields) == 3:
+		case (edit.Which == "lo" || edit.Which == "up" || edit.Which == "fix") && len(fields) == 4:
+			edit.Value, err = parseScenValue(fields[3])
+		default:
+			return edit, errors.New("expected 'bound column lo|up|fix|free [value]'")
+		}
+
+	case "rhs":
+		if len(fields) != 3 {
+			return edit, errors.New("expected 'rhs row value'")
+		}
+		edit.Row = fields[1]
+		edit.Value, err = parseScenValue(fields[2])
+
+	case "coef":
+		if len(fields) != 4 {
+			return edit, errors.New("expected 'coef row column value'")
+		}
+		edit.Row, edit.Col = fields[1], fields[2]
+		edit.Value, err = parseScenValue(fields[3])
+
+	case "delrow":
+		if len(fields) != 2 {
+			return edit, errors.New("expected 'delrow row'")
+		}
+		edit.Row = fields[1]
+
+	case "delcol":
+		if len(fields) != 2 {
+			return edit, errors.New("expected 'delcol column'")
+		}
+		edit.Col = fields[1]
+
+	default:
+		return edit, errors.Errorf("unknown edit '%s'", fields[0])
+	}
+
+	return edit, err
+}
+
+//==============================================================================
+
+// readScenarios reads the scenarios held in a file. A line "scenario name"
+// starts a scenario, and each following line holds one edit:
+//   bound column lo|up|fix value, or bound column free
+//   rhs row value
+//   coef row column value
+//   delrow row
+//   delcol column
+// Edits before the first scenario line make up a scenario named after the
+// file. Blank lines and lines starting with '#' are ignored. All lines are
+// checked, and the errors found are reported together.
+// In case of failure, function returns an error.
+func readScenarios(fileName string) ([]scenario, error) {
+	var scens  []scenario          // scenarios read
+	var failed []string            // lines which could not be read
+	var names  = make(map[string]bool)  // scenario names in use
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot open scenario file %s", fileName)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if strings.ToLower(fields[0]) == "scenario" {
+			if len(fields) != 2 {
+				failed = append(failed, fmt.Sprintf("line %d: expected 'scenario name'", lineNum))
+				continue
+			}
+			if names[fields[1]] {
+				failed = append(failed, fmt.Sprintf("line %d: scenario '%s' defined twice",
+					lineNum, fields[1]))
+			}
+			names[fields[1]] = true
+			scens = append(scens, scenario{Name: fields[1]})
+			continue
+		}
+
+		edit, err := parseScenEdit(fields)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("line %d: %s", lineNum, err))
+			continue
+		}
+		edit.Line = lineNum
+		if len(scens) == 0 {
+			name := strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+			names[name] = true
+			scens = append(scens, scenario{Name: name})
+		}
+		scens[len(scens) - 1].Edits = append(scens[len(scens) - 1].Edits, edit)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "Cannot read scenario file %s", fileName)
+	}
+	if len(failed) > 0 {
+		return nil, errors.Errorf("Errors in scenario file:\n  %s", strings.Join(failed, "\n  "))
+	}
+	if len(scens) == 0 {
+		return nil, errors.Errorf("Scenario file %s holds no scenarios", fileName)
+	}
+
+	return scens, nil
+}
+
+//==============================================================================
+
+// applyEdit applies one edit to the model, finding rows and columns by name.
+// In case of failure, function returns an error.
+func applyEdit(work *lpModel, edit scenEdit) error {
+
+	iRow, iCol := -1, -1
+	if edit.Row != "" {
+		if iRow = findRow(*work, edit.Row); iRow < 0 {
+			return errors.Errorf("row '%s' not found", edit.Row)
+		}
+	}
+	if edit.Col != "" {
+		if iCol = findCol(*work, edit.Col); iCol < 0 {
+			return errors.Errorf("column '%s' not found", edit.Col)
+		}
+	}
+
+	switch edit.Op {
+	case "bound":
+		col := &work.Cols[iCol]
+		switch edit.Which {
+		case "lo":
+			col.BndLo = edit.Value
+		case "up":
+			col.BndUp = edit.Value
+		case "fix":
+			col.BndLo, col.BndUp = edit.Value, edit.Value
+		case "free":
+			col.BndLo, col.BndUp = lpo.Neginf, lpo.Plinfy
+		}
+
+	case "rhs":
+		if isFreeRow(work.Rows[iRow]) {
+			return errors.Errorf("row '%s' is a free row", edit.Row)
+		}
+		setRhs(&work.Rows[iRow], edit.Value)
+
+	case "coef":
+		setCoef(work, iRow, iCol, edit.Value)
+
+	case "delrow":
+		if iRow == work.ObjRow {
+			return errors.New("cannot delete the objective row")
+		}
+		var rows, cols []int  // rows and columns kept
+		for i := range work.Rows {
+			if i != iRow && i != work.ObjRow {
+				rows = append(rows, i)
+			}
+		}
+		for j := range work.Cols {
+			cols = append(cols, j)
+		}
+		*work = subModel(*work, rows, cols)
+
+	case "delcol":
+		var rows, cols []int  // rows and columns kept
+		for i := range work.Rows {
+			if i != work.ObjRow {
+				rows = append(rows, i)
+			}
+		}
+		for j := range work.Cols {
+			if j != iCol {
+				cols = append(cols, j)
+			}
+		}
+		*work = subModel(*work, rows, cols)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// applyScenario returns a copy of the model with the edits of the scenario
+// applied in order. Rows and columns are found by name, so an edit may not
+// refer to one deleted earlier in the scenario. In case of failure, function
+// returns an error.
+func applyScenario(model lpModel, scen scenario) (lpModel, error) {
+
+	work := copyModel(model)
+
+	for _, edit := range scen.Edits {
+		if err := applyEdit(&work, edit); err != nil {
+			return work, errors.Errorf("line %d: %s", edit.Line, err)
+		}
+	}
+
+	return work, nil
+}
+
+//==============================================================================
+
+// solveScenario solves the model with the solver selected and no reductions.
+// Any failure other than an interrupt is recorded in the status of the result.
+// In case of interrupt, function returns an error.
+func solveScenario(ctx context.Context, name string, model lpModel, useCoin bool) (scenResult,
+	error) {
+
+	result := scenResult{Name: name, Status: "optimal", Model: model}
+
+	restoreModel(model)
+	psCtrl := lpo.PsCtrl{RunSolver: true, MaxIter: paramMaxIter(10)}
+
+	err := solveInContext(ctx, useCoin, psCtrl, &result.Soln)
+	if err == errInterrupted || err == errTimeLimit {
+		return result, err
+	}
+	if err != nil {
+		result.Status = err.Error()
+	}
+
+	return result, nil
+}
+
+//==============================================================================
+
+// scenVarbs returns the columns of the base model whose values change most from
+// the base solution in any of the scenarios solved, largest change first, or
+// none if the base model was not solved.
+func scenVarbs(results []scenResult) []string {
+	var names  []string                    // columns which change
+	var change = make(map[string]float64)  // largest change of each column
+
+	base := results[0]
+	if base.Status != "optimal" {
+		return nil
+	}
+
+	for _, result := range results[1:] {
+		if result.Status != "optimal" {
+			continue
+		}
+		for _, col := range base.Model.Cols {
+			varb, ok := result.Soln.VarMap[col.Name]
+			if !ok {
+				continue
+			}
+			diff := math.Abs(varb.Value - base.Soln.VarMap[col.Name].Value)
+			if diff > verifyTol && diff > change[col.Name] {
+				if _, seen := change[col.Name]; !seen {
+					names = append(names, col.Name)
+				}
+				change[col.Name] = diff
+			}
+		}
+	}
+
+	sort.SliceStable(names, func(a, b int) bool { return change[names[a]] > change[names[b]] })
+	if len(names) > scenMaxVarbs {
+		names = names[:scenMaxVarbs]
+	}
+
+	return names
+}
+
+//==============================================================================
+
+// scenTable returns the comparison of the results as a table of strings, with
+// one column per result and one line each for the status, the objective value,
+// its change from the base, and the variables compared. A variable deleted by a
+// scenario is shown as "deleted".
+func scenTable(results []scenResult, varbs []string) [][]string {
+	var table [][]string  // lines of the table, starting with the heading
+
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'g', 8, 64)
+	}
+
+	heading := []string{""}
+	for _, result := range results {
+		heading = append(heading, result.Name)
+	}
+	table = append(table, heading)
+
+	status    := []string{"status"}
+	objective := []string{"objective"}
+	change    := []string{"change"}
+	for _, result := range results {
+		status = append(status, firstLine(result.Status))
+		if result.Status != "optimal" {
+			objective = append(objective, "-")
+			change    = append(change, "-")
+			continue
+		}
+		objective = append(objective, format(result.Soln.ObjVal))
+		if results[0].Status == "optimal" {
+			change = append(change, format(result.Soln.ObjVal - results[0].Soln.ObjVal))
+		} else {
+			change = append(change, "-")
+		}
+	}
+	table = append(table, status, objective, change)
+
+	for _, name := range varbs {
+		line := []string{name}
+		for _, result := range results {
+			varb, ok := result.Soln.VarMap[name]
+			switch {
+			case result.Status != "optimal":
+				line = append(line, "-")
+			case !ok:
+				line = append(line, "deleted")
+			default:
+				line = append(line, format(varb.Value))
+			}
+		}
+		table = append(table, line)
+	}
+
+	return table
+}
+
+//==============================================================================
+
+// printScenTable prints the comparison table with the results side by side.
+// The function returns no values.
+func printScenTable(table [][]string) {
+
+	fmt.Printf("\n")
+	for _, line := range table {
+		fmt.Printf("%-16s", line[0])
+		for _, cell := range line[1:] {
+			if len(cell) > 15 {
+				cell = cell[:15]
+			}
+			fmt.Printf(" %15s", cell)
+		}
+		fmt.Printf("\n")
+	}
+
+}
+
+//==============================================================================
+
+// writeScenCsv writes the comparison table to a CSV file.
+// In case of failure, function returns an error.
+func writeScenCsv(fileName string, table [][]string) error {
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create file %s", fileName)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.WriteAll(table)
+	if err = w.Error(); err != nil {
+		return errors.Wrapf(err, "Failed to write file %s", fileName)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// wpScenario applies the scenarios read from a file, given on the command line
+// as "scenario file [name ...]", to copies of the model held in the lpo data
+// structures, solves the base model and each scenario, and compares their
+// objective values and key variables side by side. All scenarios in the file
+// are used unless some are named. The data structures and the lpo solution are
+// left unchanged. In case of failure, function returns an error.
+func wpScenario() error {
+	var chosen  []scenario    // scenarios applied
+	var results []scenResult  // results of the base model and each scenario
+	var varbs   []string      // variables compared
+	var fileCsv string        // CSV output file
+	var colNames = make(map[string]bool)  // column names of the base model
+	var err     error         // error returned by functions called
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+		return errors.New("wpScenario failed, model not defined")
+	}
+	if lpo.ObjRow < 0 || lpo.ObjRow >= len(lpo.Rows) {
+		return errors.New("wpScenario failed, model has no objective row")
+	}
+
+	fileName := promptArg(0, "Enter scenario file name: ")
+	scens, err := readScenarios(fileName)
+	if err != nil {
+		return errors.Wrap(err, "wpScenario failed")
+	}
+
+	if len(cmdArgs) > 1 {
+		for _, name := range cmdArgs[1:] {
+			found := false
+			for _, scen := range scens {
+				if scen.Name == name {
+					chosen = append(chosen, scen)
+					found  = true
+				}
+			}
+			if !found {
+				return errors.Errorf("wpScenario failed, scenario '%s' not in file", name)
+			}
+		}
+	} else {
+		chosen = scens
+	}
+
+	// Every scenario is applied before any solve, so that errors in the file
+	// are found at once.
+	model := saveModel()
+	defer restoreModel(model)
+
+	scenModels := make([]lpModel, len(chosen))
+	for k, scen := range chosen {
+		if scenModels[k], err = applyScenario(model, scen); err != nil {
+			return errors.Wrapf(err, "wpScenario failed, scenario '%s'", scen.Name)
+		}
+	}
+
+	for _, col := range model.Cols {
+		colNames[col.Name] = true
+	}
+	fmt.Printf("Enter variables to compare, separated by commas, or <CR> for those changing most: ")
+	if varbs, err = splitNames(readLine(), colNames, "column"); err != nil {
+		return errors.Wrap(err, "wpScenario failed")
+	}
+
+	useCoin := wpGetUseCoin()
+
+	ctx, endSolve := beginSolve(0)
+	defer endSolve()
+
+	fmt.Printf("Solving base model and %d scenarios, press Ctrl-C to stop.\n", len(chosen))
+	result, err := solveScenario(ctx, "BASE", model, useCoin)
+	if err != nil {
+		return errors.Wrap(err, "wpScenario failed")
+	}
+	results = append(results, result)
+
+	for k, scen := range chosen {
+		fmt.Printf("Solving scenario '%s' (%d edits).\n", scen.Name, len(scen.Edits))
+		if result, err = solveScenario(ctx, scen.Name, scenModels[k], useCoin); err != nil {
+			return errors.Wrap(err, "wpScenario failed")
+		}
+		results = append(results, result)
+	}
+
+	if len(varbs) == 0 {
+		varbs = scenVarbs(results)
+	}
+	table := scenTable(results, varbs)
+	printScenTable(table)
+
+	fmt.Printf("\nEnter CSV file name for the comparison or <CR> for none: ")
+	fmt.Scanln(&fileCsv)
+	if fileCsv != "" {
+		if custEnvOn {
+			fileCsv = dSrcDev + fileCsv + ".csv"
+		}
+		if err = writeScenCsv(fileCsv, table); err != nil {
+			return errors.Wrap(err, "wpScenario failed")
+		}
+		fmt.Printf("Comparison written to '%s'.\n", fileCsv)
+	}
+
+	return nil
+}
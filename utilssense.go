@@ -0,0 +1,283 @@
+// This file contains the handling of the objective sense. lpo always minimizes
+// and does not accept the OBJSENSE section of free MPS files, so the sense is
+// kept here and applied around every lpo read, write, and solve.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Objective senses, as written in the OBJSENSE section of an MPS file.
+
+const senseMin = "MIN"
+const senseMax = "MAX"
+
+// Objective sense of the model held in the lpo data structures. It is set when
+// a model is read, and may be changed by the user before a solve.
+
+var objSense string = senseMin
+
+//==============================================================================
+
+// parseSense returns the objective sense named by the string, accepting the
+// short and long forms used in MPS files in any case. If the string is not a
+// sense, the function returns false.
+func parseSense(s string) (string, bool) {
+
+	switch strings.ToUpper(s) {
+	case "MIN", "MINIMIZE":
+		return senseMin, true
+	case "MAX", "MAXIMIZE":
+		return senseMax, true
+	}
+
+	return "", false
+}
+
+//==============================================================================
+
+// scanObjSense reads the header of an MPS file, up to the ROWS section, and
+// returns the sense given by its OBJSENSE section together with the numbers
+// of the lines holding the section, which lpo cannot read. The section may be
+// written on one line, "OBJSENSE MAX", or with the sense on the next line. If
+// the file has no OBJSENSE section, the sense returned is MIN and no lines are
+// returned. In case of failure, function returns an error.
+func scanObjSense(fileName string) (string, []int, error) {
+	var sense  string  // sense found
+	var lines  []int   // lines holding the OBJSENSE section
+	var inSect bool    // previous line was an OBJSENSE header without a sense
+
+	f, err := os.Open(fileName)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "Cannot open MPS file %s", fileName)
+	}
+	defer f.Close()
+
+	sense   = senseMin
+	scanner := bufio.NewScanner(f)
+	for lineNum := 0; scanner.Scan(); lineNum++ {
+		line   := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(line, "*") {
+			continue
+		}
+
+		if inSect {
+			inSect = false
+			if s, ok := parseSense(fields[0]); ok && len(fields) == 1 {
+				sense = s
+				lines = append(lines, lineNum)
+				continue
+			}
+			return "", nil, errors.Errorf("Invalid OBJSENSE section in %s, line %d", fileName, lineNum + 1)
+		}
+
+		header := strings.ToUpper(fields[0])
+		if header == "ROWS" {
+			break
+		}
+		if header != "OBJSENSE" {
+			continue
+		}
+
+		lines = append(lines, lineNum)
+		if len(fields) == 1 {
+			inSect = true
+			continue
+		}
+		s, ok := parseSense(fields[1])
+		if !ok || len(fields) > 2 {
+			return "", nil, errors.Errorf("Invalid OBJSENSE section in %s, line %d", fileName, lineNum + 1)
+		}
+		sense = s
+	}
+
+	if err = scanner.Err(); err != nil {
+		return "", nil, errors.Wrapf(err, "Cannot read MPS file %s", fileName)
+	}
+
+	return sense, lines, nil
+}
+
+//==============================================================================
+
+// copyMpsSense copies an MPS file, leaving out the lines listed in skip, and
+// inserting an OBJSENSE section after the NAME line if the sense is MAX. The
+// destination may be the same file as the source.
+// In case of failure, function returns an error.
+func copyMpsSense(src, dst string, skip []int, sense string) error {
+	var out      []string      // lines written to the destination
+	var inserted bool          // OBJSENSE section already inserted
+	var skipLine = make(map[int]bool)  // lines left out
+
+	for _, lineNum := range skip {
+		skipLine[lineNum] = true
+	}
+
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot read MPS file %s", src)
+	}
+
+	section := []string{"OBJSENSE", "    " + senseMax}
+	lines   := strings.SplitAfter(string(data), "\n")
+	if sense != senseMax {
+		inserted = true
+	}
+
+	for lineNum, line := range lines {
+		if skipLine[lineNum] {
+			continue
+		}
+		out = append(out, line)
+		if !inserted && strings.HasPrefix(strings.ToUpper(line), "NAME") {
+			out = append(out, strings.Join(section, "\n") + "\n")
+			inserted = true
+		}
+	}
+
+	// A file without a NAME line gets the section at the top.
+	if !inserted {
+		out = append([]string{strings.Join(section, "\n") + "\n"}, out...)
+	}
+
+	if err = ioutil.WriteFile(dst, []byte(strings.Join(out, "")), 0644); err != nil {
+		return errors.Wrapf(err, "Cannot write MPS file %s", dst)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// readMpsSense reads an MPS file into the lpo data structures, and sets the
+// objective sense from its OBJSENSE section, or to MIN if it has none. A file
+// with an OBJSENSE section is read from a copy without it.
+// In case of failure, function returns an error.
+func readMpsSense(fileName string) error {
+
+	sense, skip, err := scanObjSense(fileName)
+	if err != nil {
+		return err
+	}
+
+	readName := fileName
+	if len(skip) > 0 {
+		tmp, err := ioutil.TempFile("", "runopt_sense")
+		if err != nil {
+			return errors.Wrap(err, "Cannot create temp file")
+		}
+		tmp.Close()
+		defer os.Remove(tmp.Name())
+
+		if err = copyMpsSense(fileName, tmp.Name(), skip, senseMin); err != nil {
+			return err
+		}
+		readName = tmp.Name()
+	}
+
+	if err = lpo.ReadMpsFile(readName); err != nil {
+		return err
+	}
+
+	objSense = sense
+	return nil
+}
+
+//==============================================================================
+
+// writeMpsSense writes the model held in the lpo data structures to an MPS
+// file, adding an OBJSENSE section if the objective is to be maximized.
+// In case of failure, function returns an error.
+func writeMpsSense(fileName string) error {
+
+	if err := lpo.WriteMpsFile(fileName); err != nil {
+		return err
+	}
+
+	if objSense == senseMax {
+		return copyMpsSense(fileName, fileName, nil, senseMax)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// negateObjective changes the sign of every coefficient of the objective row in
+// the lpo data structures. The function returns no values.
+func negateObjective() {
+
+	if lpo.ObjRow < 0 || lpo.ObjRow >= len(lpo.Rows) {
+		return
+	}
+
+	for _, iElem := range lpo.Rows[lpo.ObjRow].HasElems {
+		lpo.Elems[iElem].Value = -lpo.Elems[iElem].Value
+	}
+}
+
+//==============================================================================
+
+// negateSoln changes the sign of the objective value, the reduced costs and the
+// duals of a solution, turning the solution of the minimization of the negated
+// objective into that of the maximization. The function returns no values.
+func negateSoln(psSoln *lpo.PsSoln) {
+
+	psSoln.ObjVal = -psSoln.ObjVal
+
+	for name, varb := range psSoln.VarMap {
+		varb.ReducedCost = -varb.ReducedCost
+		psSoln.VarMap[name] = varb
+	}
+
+	for name, con := range psSoln.ConMap {
+		con.Pi   = -con.Pi
+		con.Dual = -con.Dual
+		psSoln.ConMap[name] = con
+	}
+}
+
+//==============================================================================
+
+// wpGetObjSense prompts the user for the objective sense, offering the default
+// provided, and returns the sense chosen.
+func wpGetObjSense(defaultSense string) string {
+	var userString string  // input provided by user
+
+	fmt.Printf("Enter objective sense [MIN|MAX] or <CR> for %s: ", defaultSense)
+	fmt.Scanln(&userString)
+
+	if sense, ok := parseSense(userString); ok {
+		return sense
+	}
+	if userString != "" {
+		fmt.Printf("'%s' is not a valid sense, using %s.\n", userString, defaultSense)
+	}
+
+	return defaultSense
+}
+
+//==============================================================================
+
+// reduceMatrixSense calls ReduceMatrix on the model held in the lpo data
+// structures, which lpo reduces as a minimization, so the objective of a model
+// to be maximized is negated around the call.
+// In case of failure, function returns an error.
+func reduceMatrixSense(psCtrl lpo.PsCtrl) error {
+
+	if objSense == senseMax {
+		negateObjective()
+		defer negateObjective()
+	}
+
+	return lpo.ReduceMatrix(psCtrl)
+}
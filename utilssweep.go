@@ -0,0 +1,364 @@
+// This file contains the parametric sweep of a right-hand side or objective
+// coefficient, which solves the model at each point of a range and tabulates
+// the results.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Largest number of points in a sweep.
+
+const sweepMaxPoints = 10000
+
+// Name of the auxiliary row and column added to the model when a sweep is warm
+// started through gpx.
+
+const sweepAuxName = "SWEEP_AUX"
+
+// sweepSpec describes a sweep.
+type sweepSpec struct {
+	Name   string     // row or column swept
+	IsRow  bool       // right-hand side of a row is swept, not an objective coefficient
+	Index  int        // index of the row or column in the model
+	Base   float64    // value of the right-hand side or coefficient in the model
+	Values []float64  // values at which the model is solved
+	Varbs  []string   // variables whose values are tabulated
+	Duals  []string   // rows whose duals are tabulated
+}
+
+// sweepPoint holds the results at one point of a sweep.
+type sweepPoint struct {
+	Value  float64    // value of the right-hand side or coefficient
+	Status string     // "optimal", or the reason no solution was obtained
+	ObjVal float64    // objective function value
+	Varbs  []float64  // values of the variables tabulated
+	Duals  []float64  // duals of the rows tabulated
+}
+
+//==============================================================================
+
+// sweepValues returns the points from start to end in steps of step, including
+// end if it falls on a step. In case of failure, function returns an error.
+func sweepValues(start, end, step float64) ([]float64, error) {
+	var values []float64  // points of the sweep
+
+	if step == 0 || (end - start) / step < 0 {
+		return nil, errors.Errorf("Step %g does not lead from %g to %g", step, start, end)
+	}
+
+	count := int(math.Floor((end - start) / step + 1.0e-9)) + 1
+	if count > sweepMaxPoints {
+		return nil, errors.Errorf("Sweep has %d points, the limit is %d", count, sweepMaxPoints)
+	}
+
+	for i := 0; i < count; i++ {
+		values = append(values, start + float64(i) * step)
+	}
+
+	return values, nil
+}
+
+//==============================================================================
+
+// sweepModel returns a copy of the model with the right-hand side or objective
+// coefficient swept set to the value.
+func sweepModel(model lpModel, spec sweepSpec, value float64) lpModel {
+
+	sweep := copyModel(model)
+
+	if spec.IsRow {
+		setRhs(&sweep.Rows[spec.Index], value)
+	} else {
+		setCoef(&sweep, sweep.ObjRow, spec.Index, value)
+	}
+
+	return sweep
+}
+
+//==============================================================================
+
+// pointFromSoln returns the results at one point of the sweep from the lpo
+// solution obtained there.
+func pointFromSoln(spec sweepSpec, value float64, soln lpo.PsSoln) sweepPoint {
+
+	point := sweepPoint{Value: value, Status: "optimal", ObjVal: soln.ObjVal}
+	for _, name := range spec.Varbs {
+		point.Varbs = append(point.Varbs, soln.VarMap[name].Value)
+	}
+	for _, name := range spec.Duals {
+		point.Duals = append(point.Duals, soln.ConMap[name].Pi)
+	}
+
+	return point
+}
+
+//==============================================================================
+
+// sweepParallel solves every point of the sweep independently, with the solver
+// selected, in a pool of worker processes as for the batch solve. Each point is
+// written to its own MPS file, with the objective sense of the model.
+// In case of failure, function returns an error.
+func sweepParallel(model lpModel, spec sweepSpec, useCoin bool, numWorkers int) ([]sweepPoint, error) {
+	var files  []string      // MPS file of each point
+	var points []sweepPoint  // results of the sweep
+
+	workDir, err := ioutil.TempDir("", "runopt_sweep")
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot create work directory")
+	}
+	defer os.RemoveAll(workDir)
+
+	current := saveModel()
+	for i, value := range spec.Values {
+		fileName := filepath.Join(workDir, fmt.Sprintf("point_%05d.mps", i))
+		restoreModel(sweepModel(model, spec, value))
+		if err = writeMpsSense(fileName); err != nil {
+			restoreModel(current)
+			return nil, errors.Wrap(err, "Cannot write model for sweep")
+		}
+		files = append(files, fileName)
+	}
+	restoreModel(current)
+
+	psCtrl  := lpo.PsCtrl{RunSolver: true, MaxIter: paramMaxIter(10)}
+	entries := runBatch(files, useCoin, psCtrl, numWorkers, 0)
+
+	for i, entry := range entries {
+		point := sweepPoint{Value: spec.Values[i], Status: entry.Status}
+		if entry.Status == "optimal" {
+			point = pointFromSoln(spec, spec.Values[i], entry.Result.PsSoln)
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+//==============================================================================
+
+// printSweep prints the results of the sweep as a table. The function returns
+// no values.
+func printSweep(spec sweepSpec, points []sweepPoint) {
+
+	fmt.Printf("\n%15s %15s", strings.ToUpper(spec.Name), "OBJECTIVE")
+	for _, name := range spec.Varbs {
+		fmt.Printf(" %15s", name)
+	}
+	for _, name := range spec.Duals {
+		fmt.Printf(" %15s", "DUAL " + name)
+	}
+	fmt.Printf("\n")
+
+	for _, point := range points {
+		fmt.Printf("%15g", point.Value)
+		if point.Status != "optimal" {
+			fmt.Printf(" %s\n", firstLine(point.Status))
+			continue
+		}
+		fmt.Printf(" %15e", point.ObjVal)
+		for _, value := range point.Varbs {
+			fmt.Printf(" %15e", value)
+		}
+		for _, value := range point.Duals {
+			fmt.Printf(" %15e", value)
+		}
+		fmt.Printf("\n")
+	}
+
+}
+
+//==============================================================================
+
+// writeSweepCsv writes the results of the sweep to a CSV file, one line per
+// point. In case of failure, function returns an error.
+func writeSweepCsv(fileName string, spec sweepSpec, points []sweepPoint) error {
+	var header []string  // column headings
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create file %s", fileName)
+	}
+	defer f.Close()
+
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	header = append(header, spec.Name, "status", "objective")
+	header = append(header, spec.Varbs...)
+	for _, name := range spec.Duals {
+		header = append(header, "dual_" + name)
+	}
+
+	w := csv.NewWriter(f)
+	w.Write(header)
+	for _, point := range points {
+		record := []string{format(point.Value), firstLine(point.Status)}
+		if point.Status != "optimal" {
+			w.Write(record)
+			continue
+		}
+		record = append(record, format(point.ObjVal))
+		for _, value := range point.Varbs {
+			record = append(record, format(value))
+		}
+		for _, value := range point.Duals {
+			record = append(record, format(value))
+		}
+		w.Write(record)
+	}
+
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return errors.Wrapf(err, "Failed to write file %s", fileName)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// splitNames returns the names in a comma-separated list, checking that each
+// is in the set of names provided. In case of failure, function returns an
+// error.
+func splitNames(list string, known map[string]bool, kind string) ([]string, error) {
+	var names []string  // names found
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			return nil, errors.Errorf("'%s' is not a %s of the model", name, kind)
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+//==============================================================================
+
+// wpSweep solves the model held in the lpo data structures over a range of
+// values of a right-hand side or objective coefficient, given on the command
+// line as "sweep name start end step", and tabulates the objective value and
+// the values and duals chosen at each point. With Cplex the sweep is warm
+// started through gpx; otherwise, or if that fails, the points are solved in
+// parallel. The data structures and the lpo solution are left unchanged.
+// In case of failure, function returns an error.
+func wpSweep() error {
+	var spec     sweepSpec     // sweep to be made
+	var points   []sweepPoint  // results of the sweep
+	var model    lpModel       // original model
+	var bounds   [3]float64    // start, end and step
+	var fileCsv  string        // CSV output file
+	var rowNames = make(map[string]bool)  // constraint row names
+	var colNames = make(map[string]bool)  // column names
+	var err      error         // error returned by functions called
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+		return errors.New("wpSweep failed, model not defined")
+	}
+	if lpo.ObjRow < 0 || lpo.ObjRow >= len(lpo.Rows) {
+		return errors.New("wpSweep failed, model has no objective row")
+	}
+	model = saveModel()
+
+	for _, row := range model.Rows {
+		if !isFreeRow(row) {
+			rowNames[row.Name] = true
+		}
+	}
+	for _, col := range model.Cols {
+		colNames[col.Name] = true
+	}
+
+	spec.Name  = promptArg(0, "Enter row (for its RHS) or column (for its cost) to sweep: ")
+	spec.Index = -1
+	for i, row := range model.Rows {
+		if row.Name == spec.Name && !isFreeRow(row) {
+			spec.IsRow, spec.Index, spec.Base = true, i, rhsBase(row)
+		}
+	}
+	if spec.Index < 0 {
+		costs := objCoefs(model)
+		for j, col := range model.Cols {
+			if col.Name == spec.Name {
+				spec.Index, spec.Base = j, costs[j]
+			}
+		}
+	}
+	if spec.Index < 0 {
+		return errors.Errorf("wpSweep failed, '%s' is not a row or column", spec.Name)
+	}
+
+	for i, prompt := range []string{"Enter start value: ", "Enter end value: ", "Enter step: "} {
+		userString := promptArg(i + 1, prompt)
+		if bounds[i], err = strconv.ParseFloat(userString, 64); err != nil {
+			return errors.Errorf("'%s' is not a valid number.", userString)
+		}
+	}
+	if spec.Values, err = sweepValues(bounds[0], bounds[1], bounds[2]); err != nil {
+		return errors.Wrap(err, "wpSweep failed")
+	}
+
+	fmt.Printf("Enter variables to tabulate, separated by commas, or <CR> for none: ")
+	if spec.Varbs, err = splitNames(readLine(), colNames, "column"); err != nil {
+		return errors.Wrap(err, "wpSweep failed")
+	}
+
+	fmt.Printf("Enter rows whose duals to tabulate, separated by commas, or <CR> for none: ")
+	if spec.Duals, err = splitNames(readLine(), rowNames, "row"); err != nil {
+		return errors.Wrap(err, "wpSweep failed")
+	}
+
+	useCoin := wpGetUseCoin()
+
+	fmt.Printf("\nSweeping %s from %g to %g, %d points, press Ctrl-C to stop.\n", spec.Name,
+		bounds[0], bounds[1], len(spec.Values))
+
+	err = errors.New("warm start requires Cplex")
+	if !useCoin {
+		// GPX_EXCLUDED: Comment out the following line if gpx is not installed.
+		points, err = gpxSweep(model, spec)
+	}
+	if err == errInterrupted {
+		return errors.Wrap(err, "wpSweep failed")
+	}
+	if err != nil {
+		fmt.Printf("Not warm started (%s), solving points in parallel.\n", firstLine(err.Error()))
+		if points, err = sweepParallel(model, spec, useCoin, runtime.NumCPU()); err != nil {
+			return errors.Wrap(err, "wpSweep failed")
+		}
+	}
+
+	printSweep(spec, points)
+
+	fmt.Printf("\nEnter CSV file name for the sweep or <CR> for none: ")
+	fmt.Scanln(&fileCsv)
+	if fileCsv != "" {
+		if custEnvOn {
+			fileCsv = dSrcDev + fileCsv + ".csv"
+		}
+		if err = writeSweepCsv(fileCsv, spec, points); err != nil {
+			return errors.Wrap(err, "wpSweep failed")
+		}
+		fmt.Printf("Sweep written to '%s'.\n", fileCsv)
+	}
+
+	return nil
+}
@@ -0,0 +1,410 @@
+// This file contains the concurrent tightening of column bounds, in which the
+// rows of the model are shared among goroutines on each pass. It is a separate
+// algorithm from lpo.TightenBounds, whose bounds it may be compared with.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Smallest change of a bound, relative to its size, counted as a tightening.
+// Smaller changes are ignored, so that the passes come to an end.
+
+const tightenTol = 1e-7
+
+// Distance from an integer within which an implied bound of an integer column
+// is taken to be that integer before rounding.
+
+const tightenIntTol = 1e-6
+
+// Largest magnitude of an implied bound replacing an infinite bound. Larger
+// bounds do not help the solvers and harm the numerics, so they are not used.
+
+const tightenMaxBound = 1e10
+
+// tightenPass holds the outcome of one pass of the bound tightening.
+type tightenPass struct {
+	Pass    int            // pass number, from 1
+	Lower   int            // lower bounds tightened
+	Upper   int            // upper bounds tightened
+	Elapsed time.Duration  // time taken by the pass
+}
+
+//==============================================================================
+
+// impliedBounds tightens newLo and newUp, indexed by column, with the bounds
+// implied for the columns of a row by the limits of the row and the range of
+// activity of its other columns, given the current bounds bndLo and bndUp.
+// The function returns no values.
+func impliedBounds(model lpModel, iRow int, bndLo, bndUp, newLo, newUp []float64) {
+	var minAct, maxAct float64  // finite parts of the smallest and largest activity
+	var minInf, maxInf int      // infinite contributions to either activity
+
+	row := model.Rows[iRow]
+	if iRow == model.ObjRow || isFreeRow(row) || len(row.HasElems) == 0 {
+		return
+	}
+
+	// The smallest and largest contributions of a column to the activity.
+	contrib := func(a float64, iCol int) (float64, float64) {
+		if a > 0 {
+			return a * bndLo[iCol], a * bndUp[iCol]
+		}
+		return a * bndUp[iCol], a * bndLo[iCol]
+	}
+	infLo := func(a float64, iCol int) bool {
+		return (a > 0 && isNeginf(bndLo[iCol])) || (a < 0 && isPlinfy(bndUp[iCol]))
+	}
+	infUp := func(a float64, iCol int) bool {
+		return (a > 0 && isPlinfy(bndUp[iCol])) || (a < 0 && isNeginf(bndLo[iCol]))
+	}
+
+	for _, iElem := range row.HasElems {
+		elem := model.Elems[iElem]
+		if elem.Value == 0 {
+			continue
+		}
+		lo, up := contrib(elem.Value, elem.InCol)
+		if infLo(elem.Value, elem.InCol) {
+			minInf++
+		} else {
+			minAct += lo
+		}
+		if infUp(elem.Value, elem.InCol) {
+			maxInf++
+		} else {
+			maxAct += up
+		}
+	}
+
+	for _, iElem := range row.HasElems {
+		elem := model.Elems[iElem]
+		a, iCol := elem.Value, elem.InCol
+		if a == 0 {
+			continue
+		}
+		lo, up := contrib(a, iCol)
+
+		// Activity of the other columns of the row: x[iCol] is bounded above by
+		// the upper limit if a > 0, and below otherwise, and the reverse for
+		// the lower limit.
+		if !isPlinfy(row.RHSup) {
+			resMin, ok := minAct - lo, minInf == 0
+			if infLo(a, iCol) {
+				resMin, ok = minAct, minInf == 1
+			}
+			if ok {
+				bound := (row.RHSup - resMin) / a
+				if a > 0 {
+					newUp[iCol] = math.Min(newUp[iCol], bound)
+				} else {
+					newLo[iCol] = math.Max(newLo[iCol], bound)
+				}
+			}
+		}
+
+		if !isNeginf(row.RHSlo) {
+			resMax, ok := maxAct - up, maxInf == 0
+			if infUp(a, iCol) {
+				resMax, ok = maxAct, maxInf == 1
+			}
+			if ok {
+				bound := (row.RHSlo - resMax) / a
+				if a > 0 {
+					newLo[iCol] = math.Max(newLo[iCol], bound)
+				} else {
+					newUp[iCol] = math.Min(newUp[iCol], bound)
+				}
+			}
+		}
+	}
+
+}
+
+//==============================================================================
+
+// tightenRows runs one pass of the bound tightening on the rows of the model,
+// shared among the number of workers given in contiguous ranges, and returns
+// the bounds implied for each column. Each worker starts from the bounds given
+// and keeps the tightest bounds it finds, and these are then merged taking the
+// largest lower and smallest upper bound, so that the result does not depend on
+// the number of workers.
+func tightenRows(model lpModel, bndLo, bndUp []float64, numWorkers int) ([]float64, []float64) {
+	var wg sync.WaitGroup
+
+	if numWorkers > len(model.Rows) {
+		numWorkers = len(model.Rows)
+	}
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+
+	workLo := make([][]float64, numWorkers)
+	workUp := make([][]float64, numWorkers)
+	chunk  := (len(model.Rows) + numWorkers - 1) / numWorkers
+
+	for w := 0; w < numWorkers; w++ {
+		workLo[w] = append([]float64(nil), bndLo...)
+		workUp[w] = append([]float64(nil), bndUp...)
+
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			last := (w + 1) * chunk
+			if last > len(model.Rows) {
+				last = len(model.Rows)
+			}
+			for iRow := w * chunk; iRow < last; iRow++ {
+				impliedBounds(model, iRow, bndLo, bndUp, workLo[w], workUp[w])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	newLo, newUp := workLo[0], workUp[0]
+	for w := 1; w < numWorkers; w++ {
+		for j := range newLo {
+			newLo[j] = math.Max(newLo[j], workLo[w][j])
+			newUp[j] = math.Min(newUp[j], workUp[w][j])
+		}
+	}
+
+	return newLo, newUp
+}
+
+//==============================================================================
+
+// tightened determines whether an implied bound improves on the current one by
+// more than the tolerance, in the direction given by sign, +1 for a lower bound
+// and -1 for an upper bound.
+func tightened(current, bound float64, sign float64) bool {
+
+	if math.IsNaN(bound) || isPlinfy(bound) || isNeginf(bound) {
+		return false
+	}
+	if isPlinfy(current) || isNeginf(current) {
+		return math.Abs(bound) <= tightenMaxBound
+	}
+
+	return sign * (bound - current) > tightenTol * math.Max(1, math.Abs(current))
+}
+
+//==============================================================================
+
+// tightenBounds tightens the bounds of the columns of the model for at most the
+// number of passes given, or until a pass changes nothing, sharing the rows of
+// each pass among the number of workers given. It returns the bounds found,
+// indexed by column, and the outcome of each pass. Implied bounds of integer
+// columns are rounded. If a column is left with a lower bound above its upper
+// bound, the model is infeasible and function returns an error.
+func tightenBounds(model lpModel, numWorkers, maxPass int) ([]float64, []float64, []tightenPass, error) {
+	var passes []tightenPass  // outcome of each pass
+
+	bndLo := make([]float64, len(model.Cols))
+	bndUp := make([]float64, len(model.Cols))
+	for j, col := range model.Cols {
+		bndLo[j], bndUp[j] = col.BndLo, col.BndUp
+	}
+
+	for pass := 1; pass <= maxPass; pass++ {
+		result    := tightenPass{Pass: pass}
+		startTime := time.Now()
+
+		newLo, newUp := tightenRows(model, bndLo, bndUp, numWorkers)
+		for j, col := range model.Cols {
+			lo, up := newLo[j], newUp[j]
+			if isIntCol(col) {
+				lo = math.Ceil(lo - tightenIntTol)
+				up = math.Floor(up + tightenIntTol)
+			}
+			if tightened(bndLo[j], lo, 1) {
+				bndLo[j] = lo
+				result.Lower++
+			}
+			if tightened(bndUp[j], up, -1) {
+				bndUp[j] = up
+				result.Upper++
+			}
+			if bndLo[j] > bndUp[j] + tightenTol * math.Max(1, math.Abs(bndUp[j])) {
+				return nil, nil, passes, errors.Errorf("model infeasible, column %s has bounds %g > %g after pass %d",
+					col.Name, bndLo[j], bndUp[j], pass)
+			}
+		}
+
+		result.Elapsed = time.Since(startTime)
+		passes = append(passes, result)
+		if result.Lower + result.Upper == 0 {
+			break
+		}
+	}
+
+	return bndLo, bndUp, passes, nil
+}
+
+//==============================================================================
+
+// sameBound determines whether two bounds agree within the tightening tolerance,
+// infinite bounds agreeing with infinite bounds of the same sign.
+func sameBound(a, b float64) bool {
+
+	switch {
+	case isPlinfy(a) || isPlinfy(b):
+		return isPlinfy(a) && isPlinfy(b)
+	case isNeginf(a) || isNeginf(b):
+		return isNeginf(a) && isNeginf(b)
+	}
+
+	return math.Abs(a - b) <= tightenTol * math.Max(1, math.Abs(a))
+}
+
+//==============================================================================
+
+// compareLpoBounds runs lpo.TightenBounds on the model for the number of
+// iterations given, and compares the bounds it gives with the bounds found,
+// matching columns by name. It returns the numbers of columns whose bounds
+// agree, are tighter with lpo and are looser with lpo (a column with one bound
+// tighter and the other looser counting as tighter), and a description of the
+// first column which differs. The data structures hold the model on return.
+// In case of failure, function returns an error.
+func compareLpoBounds(model lpModel, bndLo, bndUp []float64, maxIter int) (int, int, int, string, error) {
+	var agree, tighter, looser int  // columns by outcome
+	var first  string               // first column which differs
+	var tbDone int                  // TightenBounds iterations completed
+
+	restoreModel(model)
+	defer restoreModel(model)
+
+	if err := lpo.TightenBounds(maxIter, &tbDone); err != nil {
+		return 0, 0, 0, "", errors.Wrap(err, "TightenBounds failed")
+	}
+	lpoCol := make(map[string]int)
+	for j, col := range lpo.Cols {
+		lpoCol[col.Name] = j
+	}
+
+	for j, col := range model.Cols {
+		k, ok := lpoCol[col.Name]
+		if !ok {
+			continue
+		}
+		lo, up := lpo.Cols[k].BndLo, lpo.Cols[k].BndUp
+		if sameBound(lo, bndLo[j]) && sameBound(up, bndUp[j]) {
+			agree++
+			continue
+		}
+		if (!sameBound(lo, bndLo[j]) && lo > bndLo[j]) || (!sameBound(up, bndUp[j]) && up < bndUp[j]) {
+			tighter++
+		} else {
+			looser++
+		}
+		if first == "" {
+			first = fmt.Sprintf("column %s: %s here, %s with lpo", col.Name,
+				formatLimits(bndLo[j], bndUp[j]), formatLimits(lo, up))
+		}
+	}
+
+	return agree, tighter, looser, first, nil
+}
+
+//==============================================================================
+
+// wpTighten tightens the bounds of the columns of the model held in the lpo data
+// structures from the limits of the rows, sharing the rows among the number of
+// workers given on the command line as "tighten [workers] [passes] [check]", or
+// entered at the prompt. The default number of passes is the maxiter parameter,
+// or 20. The time taken and the number of bounds tightened are reported for
+// each pass, and the bounds found replace those of the model. With "check", the
+// bounds are also compared with those of lpo.TightenBounds.
+// In case of failure, function returns an error.
+func wpTighten() error {
+	var numWorkers int    // goroutines sharing the rows
+	var err        error  // error returned by functions called
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+		return errors.New("wpTighten failed, model not defined")
+	}
+
+	// The check flag may be given in any position.
+	check := false
+	var args []string  // arguments other than the check flag
+	for _, arg := range cmdArgs {
+		if strings.ToLower(arg) == "check" {
+			check = true
+		} else {
+			args = append(args, arg)
+		}
+	}
+	cmdArgs = args
+
+	numWorkers = runtime.NumCPU()
+	userString := promptArg(0, fmt.Sprintf("Enter number of workers or <CR> for %d: ", numWorkers))
+	if userString != "" {
+		if numWorkers, err = strconv.Atoi(userString); err != nil || numWorkers < 1 {
+			return errors.Errorf("wpTighten failed, '%s' is not a valid number of workers", userString)
+		}
+	}
+
+	maxPass := paramMaxIter(20)
+	if len(cmdArgs) > 1 {
+		if maxPass, err = strconv.Atoi(cmdArgs[1]); err != nil || maxPass < 1 {
+			return errors.Errorf("wpTighten failed, '%s' is not a valid number of passes", cmdArgs[1])
+		}
+	}
+
+	model := saveModel()
+	fmt.Printf("Tightening bounds of %d columns over %d rows and %d elements, %d workers.\n",
+		len(model.Cols), len(model.Rows), len(model.Elems), numWorkers)
+
+	startTime := time.Now()
+	bndLo, bndUp, passes, err := tightenBounds(model, numWorkers, maxPass)
+
+	fmt.Printf("\n%6s %10s %10s %12s\n", "Pass", "Lower", "Upper", "Time")
+	for _, pass := range passes {
+		fmt.Printf("%6d %10d %10d %12s\n", pass.Pass, pass.Lower, pass.Upper,
+			pass.Elapsed.Round(time.Microsecond))
+	}
+	if err != nil {
+		return errors.Wrap(err, "wpTighten failed, bounds left unchanged")
+	}
+
+	if check {
+		agree, tighter, looser, first, err := compareLpoBounds(model, bndLo, bndUp, maxPass)
+		if err != nil {
+			return errors.Wrap(err, "wpTighten failed, bounds left unchanged")
+		}
+		fmt.Printf("\nCompared with lpo.TightenBounds: %d columns agree, %d are tighter with lpo, %d looser.\n",
+			agree, tighter, looser)
+		if first != "" {
+			fmt.Printf("First difference, %s.\n", first)
+		}
+	}
+
+	var lower, upper int  // bounds tightened over all passes
+	for j, col := range model.Cols {
+		if bndLo[j] != col.BndLo {
+			lower++
+		}
+		if bndUp[j] != col.BndUp {
+			upper++
+		}
+		lpo.Cols[j].BndLo, lpo.Cols[j].BndUp = bndLo[j], bndUp[j]
+	}
+
+	fmt.Printf("\n%d lower and %d upper bounds tightened in %d passes, %s.\n", lower, upper,
+		len(passes), time.Since(startTime).Round(time.Millisecond))
+	if len(passes) == maxPass && passes[len(passes) - 1].Lower + passes[len(passes) - 1].Upper > 0 {
+		fmt.Printf("The last pass still tightened bounds; more passes may tighten further.\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+// This file contains the wrapper for the analysis tools, which are commands
+// built on top of lpo rather than wrappers for individual exported functions,
+// and the helpers for reading their input.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"os"
+	"strings"
+)
+
+// Tool commands may be followed on the same line by their arguments, which
+// are stored here when the command is read. Any argument not given on the
+// command line is prompted for.
+
+var cmdArgs []string
+
+//==============================================================================
+
+// readLine reads one line from standard input and returns it without the line
+// terminator. Input is read one byte at a time, as fmt.Scanln does, so nothing
+// is buffered ahead of later prompts.
+func readLine() string {
+	var line []byte          // bytes read so far
+	var buf  = make([]byte, 1)  // single-byte read buffer
+
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n == 0 || err != nil || buf[0] == '\n' {
+			break
+		}
+		line = append(line, buf[0])
+	}
+
+	return strings.TrimRight(string(line), "\r")
+}
+
+//==============================================================================
+
+// readCommand reads a command line from the user, returning the command and
+// storing any arguments following it in cmdArgs.
+func readCommand() string {
+
+	fields := strings.Fields(readLine())
+	if len(fields) == 0 {
+		cmdArgs = nil
+		return ""
+	}
+
+	cmdArgs = fields[1:]
+	return fields[0]
+}
+
+//==============================================================================
+
+// promptArg returns the command-line argument at the position specified if it
+// was given, or otherwise displays the prompt and returns the line entered.
+func promptArg(index int, prompt string) string {
+
+	if index < len(cmdArgs) {
+		return cmdArgs[index]
+	}
+
+	fmt.Printf("%s", prompt)
+	return strings.TrimSpace(readLine())
+}
+
+//==============================================================================
+
+// wpGetPsFlags prompts the user for the matrix-reduction operations to apply,
+// using the same questions as the solve problem command, and sets the flags in
+// the control structure provided. The function returns no values.
+func wpGetPsFlags(psCtrl *lpo.PsCtrl) {
+	var flagChoice string   // choice of which options to select
+	var userString string   // input provided by user
+
+	psCtrl.DelRowNonbinding = false
+	psCtrl.DelRowSingleton  = false
+	psCtrl.DelColSingleton  = false
+	psCtrl.DelFixedVars     = false
+
+	fmt.Printf("Reductions to apply ['all' | 'none' | <CR> to set]: ")
+	fmt.Scanln(&flagChoice)
+
+	if flagChoice == "all" {
+		psCtrl.DelRowNonbinding = true
+		psCtrl.DelRowSingleton  = true
+		psCtrl.DelColSingleton  = true
+		psCtrl.DelFixedVars     = true
+	} else if flagChoice == "none" {
+		// Default state
+	} else {
+		userString = ""
+		fmt.Printf("Do you wish to run TightenBounds [Y|N]: ")
+		fmt.Scanln(&userString)
+		psCtrl.DelRowNonbinding = userString == "y" || userString == "Y"
+
+		userString = ""
+		fmt.Printf("Do you wish to remove row singletons [Y|N]: ")
+		fmt.Scanln(&userString)
+		psCtrl.DelRowSingleton = userString == "y" || userString == "Y"
+
+		userString = ""
+		fmt.Printf("Do you wish to remove column singletons [Y|N]: ")
+		fmt.Scanln(&userString)
+		psCtrl.DelColSingleton = userString == "y" || userString == "Y"
+
+		userString = ""
+		fmt.Printf("Do you wish to remove fixed variables [Y|N]: ")
+		fmt.Scanln(&userString)
+		psCtrl.DelFixedVars = userString == "y" || userString == "Y"
+	} // end else setting reduction flags
+
+}
+
+//==============================================================================
+
+// wpGetUseCoin asks the user which solver to use. It returns true if Coin-OR
+// was selected and false for Cplex.
+func wpGetUseCoin() bool {
+	var userString string   // input provided by user
+
+	fmt.Printf("Do you wish to use Coin-OR instead of Cplex [Y|N]: ")
+	fmt.Scanln(&userString)
+
+	return userString == "y" || userString == "Y"
+}
+
+//==============================================================================
+
+// runToolWrapper executes the analysis tools.
+// The display of menu items may be hidden to avoid clutter, but the command
+// options remain available even if the menu item is hidden.
+// The function is called from the main wrapper and accepts the cmdOption as an
+// argument. If the command cannot be executed because it does not match any of
+// the cases covered by this wrapper, it returns an error.
+func runToolWrapper(cmdOption string) error {
+	var err           error         // error returned by functions called
+
+	switch cmdOption {
+
+	//--------------------------------------------------------------------------
+	case "batch":
+		if err = wpBatchSolve(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "bench":
+		if err = wpBench(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "verify":
+		if err = wpVerify(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "pscheck":
+		if err = wpPresolveCheck(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "set":
+		if err = wpSetParam(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "show":
+		wpShowParams()
+
+	//--------------------------------------------------------------------------
+	case "load":
+		if err = wpLoadParams(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "objrow":
+		if err = wpObjRow(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "ranging":
+		if err = wpRanging(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "iis":
+		if err = wpFindIIS(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "elastic":
+		if err = wpElastic(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "sweep":
+		if err = wpSweep(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "scenario":
+		if err = wpScenario(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "pool":
+		if err = wpPool(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "edit":
+		if err = wpEdit(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "delete":
+		if err = wpBulkDelete(false); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "keep":
+		if err = wpBulkDelete(true); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "trace":
+		if err = wpPresolveTrace(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "postsolve":
+		if err = wpPostsolve(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "tighten":
+		if err = wpTighten(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "scale":
+		if err = wpScale(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "health":
+		if err = wpHealth(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "validate":
+		if err = wpValidate(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	case "find":
+		if err = wpFind(); err != nil {
+			fmt.Println(err)
+		}
+
+	//--------------------------------------------------------------------------
+	default:
+		return errors.Errorf("Command %s not in tools menu", cmdOption)
+
+	} // End switch on command option
+
+	return nil
+}
@@ -0,0 +1,458 @@
+// This file contains the presolve trace, which records the individual changes
+// made by ReduceMatrix to the model, in the order they were applied, and lets
+// the user browse them or export them as JSON.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// traceEvent is one change made to the model by a reduction.
+type traceEvent struct {
+	Step     int      // pre-solve operation which made the change, from 1, 0 if inferred
+	Rule     string   // reduction applied by the operation, or "inferred"
+	Kind     string   // delrow, delcol, rowlimits, colbounds or op
+	Row      string   // row changed, empty for a change to a column only
+	Col      string   // column changed, empty for a change to a row only
+	OldLo    float64  // lower limit or bound before the change
+	OldUp    float64  // upper limit or bound before the change
+	NewLo    float64  // lower limit or bound after the change
+	NewUp    float64  // upper limit or bound after the change
+	Detail   string   // description of the change
+	Inferred bool     // found by comparing the models, not read from the PSOP file
+}
+
+// presolveTrace is the record of a presolve trace, as exported to JSON.
+type presolveTrace struct {
+	Model    string        // problem name
+	Rules    []string      // reductions applied, in the order first used
+	Steps    int           // number of pre-solve operations
+	RowsFrom int           // rows before the reductions
+	RowsTo   int           // rows after the reductions
+	ColsFrom int           // columns before the reductions
+	ColsTo   int           // columns after the reductions
+	Events   []traceEvent  // changes made, in order
+}
+
+// Last trace recorded, kept so that it can be browsed again.
+
+var psTrace presolveTrace
+
+//==============================================================================
+
+// traceRules returns the reductions selected in the control structure, each as
+// a control structure applying it alone, in the order the presolve check uses.
+func traceRules(psCtrl lpo.PsCtrl) []psCheckRun {
+	var rules []psCheckRun  // reductions selected
+
+	for _, run := range psCheckRuns() {
+		ctrl := run.PsCtrl
+		if run.Name == "all" {
+			continue
+		}
+		if (ctrl.DelRowNonbinding && psCtrl.DelRowNonbinding) ||
+			(ctrl.DelRowSingleton && psCtrl.DelRowSingleton) ||
+			(ctrl.DelColSingleton && psCtrl.DelColSingleton) ||
+			(ctrl.DelFixedVars && psCtrl.DelFixedVars) {
+			rules = append(rules, run)
+		}
+	}
+
+	return rules
+}
+
+//==============================================================================
+
+// formatLimits returns the lower and upper limits as an interval, writing
+// infinite values as inf.
+func formatLimits(lo, up float64) string {
+
+	format := func(value float64) string {
+		switch {
+		case isPlinfy(value):
+			return "inf"
+		case isNeginf(value):
+			return "-inf"
+		}
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	return "[" + format(lo) + ", " + format(up) + "]"
+}
+
+//==============================================================================
+
+// limitChanges returns the changes between two versions of a model to the
+// limits of the rows and the bounds of the columns both keep, matching rows and
+// columns by name.
+func limitChanges(before, after lpModel) []traceEvent {
+	var events  []traceEvent            // changes found
+	var rowsAft = make(map[string]int)  // index of each row after
+	var colsAft = make(map[string]int)  // index of each column after
+
+	for i, row := range after.Rows {
+		rowsAft[row.Name] = i
+	}
+	for j, col := range after.Cols {
+		colsAft[col.Name] = j
+	}
+
+	for _, row := range before.Rows {
+		k, kept := rowsAft[row.Name]
+		if !kept {
+			continue
+		}
+		newRow := after.Rows[k]
+		if row.RHSlo != newRow.RHSlo || row.RHSup != newRow.RHSup || row.Type != newRow.Type {
+			events = append(events, traceEvent{Kind: "rowlimits", Row: row.Name, OldLo: row.RHSlo,
+				OldUp: row.RHSup, NewLo: newRow.RHSlo, NewUp: newRow.RHSup,
+				Detail: fmt.Sprintf("row %s limits %s %s -> %s %s", row.Name, row.Type,
+				formatLimits(row.RHSlo, row.RHSup), newRow.Type, formatLimits(newRow.RHSlo, newRow.RHSup))})
+		}
+	}
+
+	for _, col := range before.Cols {
+		k, kept := colsAft[col.Name]
+		if !kept {
+			continue
+		}
+		newCol := after.Cols[k]
+		if col.BndLo != newCol.BndLo || col.BndUp != newCol.BndUp {
+			events = append(events, traceEvent{Kind: "colbounds", Col: col.Name, OldLo: col.BndLo,
+				OldUp: col.BndUp, NewLo: newCol.BndLo, NewUp: newCol.BndUp,
+				Detail: fmt.Sprintf("column %s bounds %s -> %s", col.Name,
+				formatLimits(col.BndLo, col.BndUp), formatLimits(newCol.BndLo, newCol.BndUp))})
+		}
+	}
+
+	return events
+}
+
+//==============================================================================
+
+// psopEvents returns the changes recorded by a pre-solve operation: the rows
+// and columns it deleted, a fixed column being shown as substituted at its
+// value, or the operation alone if it deleted neither.
+func psopEvents(record psopRecord) []traceEvent {
+	var events []traceEvent  // changes recorded
+
+	numElems := func(row, col string) int {
+		var count int  // elements of the row or column
+		for _, elem := range record.Elems {
+			if (row != "" && elem.Row == row) || (col != "" && elem.Col == col) {
+				count++
+			}
+		}
+		return count
+	}
+
+	for _, row := range record.Rows {
+		events = append(events, traceEvent{Kind: "delrow", Row: row.Name, OldLo: row.RHSlo,
+			OldUp: row.RHSup, Detail: fmt.Sprintf("%s row %s %s with %d elements deleted",
+			row.Type, row.Name, formatLimits(row.RHSlo, row.RHSup), numElems(row.Name, ""))})
+	}
+	for _, col := range record.Cols {
+		detail := fmt.Sprintf("column %s %s with %d elements deleted", col.Name,
+			formatLimits(col.BndLo, col.BndUp), numElems("", col.Name))
+		if col.BndLo == col.BndUp {
+			detail = fmt.Sprintf("column %s fixed at %g substituted out of %d elements", col.Name,
+				col.BndLo, numElems("", col.Name))
+		}
+		events = append(events, traceEvent{Kind: "delcol", Col: col.Name, OldLo: col.BndLo,
+			OldUp: col.BndUp, Detail: detail})
+	}
+	if len(events) == 0 {
+		events = append(events, traceEvent{Kind: "op", Detail: "operation " + record.OpType})
+	}
+
+	for k := range events {
+		events[k].Step, events[k].Rule = record.Index, record.Rule
+	}
+
+	return events
+}
+
+//==============================================================================
+
+// tracePresolve runs ReduceMatrix on the model held in the lpo data structures
+// with the reductions selected, has it write the PSOP file of the operations it
+// applied, and returns the trace built from the operations in their order. The
+// changes to the limits of the rows and the bounds of the columns kept, which
+// the PSOP file does not give, are found by comparing the model before and
+// after. Which operation made them is not known, so they follow the operations
+// and are marked as inferred. The data structures hold the reduced model on
+// return. If the PSOP file cannot be read or holds no operations, function
+// returns an error.
+func tracePresolve(psCtrl lpo.PsCtrl) (presolveTrace, error) {
+
+	trace  := presolveTrace{Model: lpo.Name, RowsFrom: len(lpo.Rows), ColsFrom: len(lpo.Cols)}
+	before := saveModel()
+
+	f, err := ioutil.TempFile("", "psop_trace_*.txt")
+	if err != nil {
+		return trace, errors.Wrap(err, "Cannot create PSOP file")
+	}
+	filePsop := f.Name()
+	f.Close()
+	defer os.Remove(filePsop)
+
+	psCtrl.FileOutPsop = filePsop
+	if err = reduceMatrixSense(psCtrl); err != nil {
+		return trace, errors.Wrap(err, "ReduceMatrix failed")
+	}
+	if info, err := os.Stat(filePsop); err != nil || info.Size() == 0 {
+		if err = lpo.WritePsopFile(filePsop, -1); err != nil {
+			return trace, errors.Wrap(err, "Cannot write PSOP file")
+		}
+	}
+
+	records, err := readPsopFile(filePsop)
+	if err != nil {
+		return trace, errors.Wrap(err, "Cannot trace the operations of ReduceMatrix")
+	}
+	after := saveModel()
+	trace.Steps, trace.RowsTo, trace.ColsTo = len(records), len(after.Rows), len(after.Cols)
+
+	ruleSeen := make(map[string]bool)
+	for _, record := range records {
+		if !ruleSeen[record.Rule] {
+			ruleSeen[record.Rule] = true
+			trace.Rules = append(trace.Rules, record.Rule)
+		}
+		trace.Events = append(trace.Events, psopEvents(record)...)
+	}
+
+	inferred := limitChanges(before, after)
+	for _, event := range inferred {
+		event.Rule, event.Inferred = "inferred", true
+		event.Detail += " (inferred, operation not known)"
+		trace.Events = append(trace.Events, event)
+	}
+	if len(inferred) > 0 {
+		trace.Rules = append(trace.Rules, "inferred")
+	}
+
+	return trace, nil
+}
+
+//==============================================================================
+
+// printTraceSummary prints the number of changes made by each rule, by kind.
+// The function returns no values.
+func printTraceSummary(trace presolveTrace) {
+	var kinds = []string{"delrow", "delcol", "rowlimits", "colbounds", "op"}
+	var count = make(map[string]map[string]int)  // changes by rule and kind
+
+	for _, event := range trace.Events {
+		if count[event.Rule] == nil {
+			count[event.Rule] = make(map[string]int)
+		}
+		count[event.Rule][event.Kind]++
+	}
+
+	fmt.Printf("\n%-18s", "RULE")
+	for _, kind := range kinds {
+		fmt.Printf(" %10s", strings.ToUpper(kind))
+	}
+	fmt.Printf("\n")
+	for _, rule := range trace.Rules {
+		fmt.Printf("%-18s", rule)
+		for _, kind := range kinds {
+			fmt.Printf(" %10d", count[rule][kind])
+		}
+		fmt.Printf("\n")
+	}
+	fmt.Printf("\n%d changes in %d operations: rows %d -> %d, columns %d -> %d.\n", len(trace.Events),
+		trace.Steps, trace.RowsFrom, trace.RowsTo, trace.ColsFrom, trace.ColsTo)
+
+}
+
+//==============================================================================
+
+// writeTraceJson writes the trace, with only the events provided, to a JSON
+// file. In case of failure, function returns an error.
+func writeTraceJson(fileName string, trace presolveTrace, events []traceEvent) error {
+
+	trace.Events = events
+	data, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Cannot encode trace")
+	}
+	if err = ioutil.WriteFile(fileName, data, 0644); err != nil {
+		return errors.Wrapf(err, "Failed to write file %s", fileName)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// browseTrace lets the user page through the events of the trace, narrowed by
+// filters on the rule, kind, step, row or column, and export the events shown
+// as JSON. Commands are read until an empty line is entered. The function
+// returns no values.
+func browseTrace(trace presolveTrace) {
+	var filters = make(map[string]string)  // filter value by field
+
+	match := func(event traceEvent) bool {
+		for field, value := range filters {
+			switch field {
+			case "rule":
+				if !strings.EqualFold(event.Rule, value) {
+					return false
+				}
+			case "kind":
+				if event.Kind != value {
+					return false
+				}
+			case "step":
+				if strconv.Itoa(event.Step) != value {
+					return false
+				}
+			case "row":
+				if event.Row != value {
+					return false
+				}
+			case "col":
+				if event.Col != value {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	fmt.Printf("\nCommands: list, summary, rule|kind|step|row|col <value>, clear, json <file>,\n")
+	fmt.Printf("<CR> to finish.\n")
+	for {
+		var shown []traceEvent  // events passing the filters
+
+		for _, event := range trace.Events {
+			if match(event) {
+				shown = append(shown, event)
+			}
+		}
+
+		var keys []string  // filter fields set
+		for field := range filters {
+			keys = append(keys, field + "=" + filters[field])
+		}
+		sort.Strings(keys)
+		fmt.Printf("trace [%d of %d events%s]> ", len(shown), len(trace.Events),
+			strings.Join(append([]string{""}, keys...), " "))
+
+		fields := strings.Fields(readLine())
+		if len(fields) == 0 {
+			return
+		}
+
+		command := strings.ToLower(fields[0])
+		switch {
+		case command == "list":
+			fmt.Printf("%5s  %-18s %-10s %s\n", "STEP", "RULE", "KIND", "CHANGE")
+			for k, event := range shown {
+				fmt.Printf("%5d  %-18s %-10s %s\n", event.Step, event.Rule, event.Kind, event.Detail)
+				if (k + 1) % pauseAfter == 0 && k + 1 < len(shown) {
+					userString := ""
+					fmt.Printf("\nPAUSED... <CR> continue, any key to quit: ")
+					fmt.Scanln(&userString)
+					if userString != "" {
+						break
+					}
+				}
+			}
+
+		case command == "summary":
+			printTraceSummary(trace)
+
+		case command == "clear":
+			filters = make(map[string]string)
+
+		case command == "json" && len(fields) == 2:
+			fileName := fields[1]
+			if custEnvOn {
+				fileName = dSrcDev + fileName + ".json"
+			}
+			if err := writeTraceJson(fileName, trace, shown); err != nil {
+				fmt.Println(err)
+			} else {
+				fmt.Printf("%d events written to '%s'.\n", len(shown), fileName)
+			}
+
+		case len(fields) == 2 && (command == "rule" || command == "kind" || command == "step" ||
+			command == "row" || command == "col"):
+			filters[command] = fields[1]
+
+		default:
+			fmt.Printf("Unknown trace command '%s'.\n", strings.Join(fields, " "))
+		}
+	}
+
+}
+
+//==============================================================================
+
+// wpPresolveTrace runs ReduceMatrix on the model held in the lpo data
+// structures, with the reductions chosen as for the solve problem command, and
+// lets the user browse the trace of the operations it applied. With the
+// argument "view", the last trace is browsed again. The data structures are
+// left unchanged. In case of failure, function returns an error.
+func wpPresolveTrace() error {
+	var psCtrl lpo.PsCtrl  // reductions traced
+
+	if len(cmdArgs) > 0 && cmdArgs[0] == "view" {
+		if psTrace.Model == "" && len(psTrace.Events) == 0 {
+			return errors.New("wpPresolveTrace failed, no trace recorded")
+		}
+		browseTrace(psTrace)
+		return nil
+	}
+
+	if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 || len(lpo.Elems) == 0 {
+		return errors.New("wpPresolveTrace failed, model not defined")
+	}
+
+	wpGetPsFlags(&psCtrl)
+	if len(traceRules(psCtrl)) == 0 {
+		return errors.New("wpPresolveTrace failed, no reductions selected")
+	}
+	psCtrl.MaxIter = paramMaxIter(20)
+
+	model := saveModel()
+	defer restoreModel(model)
+
+	trace, err := tracePresolve(psCtrl)
+	if err != nil {
+		return errors.Wrap(err, "wpPresolveTrace failed")
+	}
+	psTrace = trace
+	printTraceSummary(trace)
+
+	// The operations read should account for every row and column deleted.
+	var rowsDel, colsDel int  // rows and columns deleted by the operations
+	for _, event := range trace.Events {
+		switch event.Kind {
+		case "delrow":
+			rowsDel++
+		case "delcol":
+			colsDel++
+		}
+	}
+	if rowsDel != trace.RowsFrom - trace.RowsTo || colsDel != trace.ColsFrom - trace.ColsTo {
+		fmt.Printf("NOTE: the PSOP file accounts for %d of %d rows and %d of %d columns deleted.\n",
+			rowsDel, trace.RowsFrom - trace.RowsTo, colsDel, trace.ColsFrom - trace.ColsTo)
+	}
+
+	browseTrace(trace)
+	return nil
+}
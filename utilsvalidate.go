@@ -0,0 +1,362 @@
+// This file contains the validation of the model held in the lpo data
+// structures, which finds inconsistencies likely to make a solve or an MPS
+// file fail, and reports each with the row, column or element concerned.
+// 01 - Oct. 19, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Severities of the findings of a validation. Errors make the model wrong or
+// unsolvable, while warnings point out likely trouble.
+
+const lintError   = "ERROR"
+const lintWarning = "WARNING"
+
+// Longest name which fits in the name fields of a fixed-format MPS file.
+
+const mpsNameLen = 8
+
+// Tolerance used when checking the limits of empty rows and the bounds of
+// integer columns.
+
+const lintTol = 1e-9
+
+// lintFinding holds one finding of a validation.
+type lintFinding struct {
+	Severity string  // lintError or lintWarning
+	Item     string  // "row", "col", "elem" or "model"
+	Name     string  // name or index of the row, column or element
+	Message  string  // description of the finding
+}
+
+//==============================================================================
+
+// isNaNOrInf determines whether a value is not a number or infinite, counting
+// the lpo infinities as infinite.
+func isNaNOrInf(value float64) bool {
+
+	return math.IsNaN(value) || isPlinfy(value) || isNeginf(value)
+}
+
+//==============================================================================
+
+// validateLinks returns the findings about the links between the elements and
+// the HasElems lists of the rows and columns: indexes out of range, elements
+// missing from the lists of their row or column, and list entries pointing to
+// elements of another row or column. It also returns whether every element has
+// a valid row and column, which the other checks need.
+func validateLinks(model lpModel) ([]lintFinding, bool) {
+	var findings []lintFinding  // findings made
+	var valid    = true         // every element has a valid row and column
+
+	elemName := func(k int) string {
+		return "#" + strconv.Itoa(k)
+	}
+
+	if model.ObjRow >= len(model.Rows) {
+		findings = append(findings, lintFinding{lintError, "model", model.Name,
+			fmt.Sprintf("objective row index %d is out of range", model.ObjRow)})
+	}
+
+	for k, elem := range model.Elems {
+		if elem.InRow < 0 || elem.InRow >= len(model.Rows) || elem.InCol < 0 || elem.InCol >= len(model.Cols) {
+			findings = append(findings, lintFinding{lintError, "elem", elemName(k),
+				fmt.Sprintf("row index %d or column index %d is out of range", elem.InRow, elem.InCol)})
+			valid = false
+		}
+	}
+
+	inList := make(map[[2]int]bool)  // (0 row | 1 col, element) pairs listed
+	for i, row := range model.Rows {
+		for _, k := range row.HasElems {
+			if k < 0 || k >= len(model.Elems) {
+				findings = append(findings, lintFinding{lintError, "row", row.Name,
+					fmt.Sprintf("HasElems lists element %d, which does not exist", k)})
+				continue
+			}
+			if model.Elems[k].InRow != i {
+				findings = append(findings, lintFinding{lintError, "row", row.Name,
+					fmt.Sprintf("HasElems lists element %s, which is in row index %d", elemName(k),
+						model.Elems[k].InRow)})
+			}
+			inList[[2]int{0, k}] = true
+		}
+	}
+	for j, col := range model.Cols {
+		for _, k := range col.HasElems {
+			if k < 0 || k >= len(model.Elems) {
+				findings = append(findings, lintFinding{lintError, "col", col.Name,
+					fmt.Sprintf("HasElems lists element %d, which does not exist", k)})
+				continue
+			}
+			if model.Elems[k].InCol != j {
+				findings = append(findings, lintFinding{lintError, "col", col.Name,
+					fmt.Sprintf("HasElems lists element %s, which is in column index %d", elemName(k),
+						model.Elems[k].InCol)})
+			}
+			inList[[2]int{1, k}] = true
+		}
+	}
+
+	if !valid {
+		return findings, false
+	}
+
+	for k, elem := range model.Elems {
+		if !inList[[2]int{0, k}] {
+			findings = append(findings, lintFinding{lintError, "elem", elemName(k),
+				fmt.Sprintf("not listed in HasElems of its row %s", model.Rows[elem.InRow].Name)})
+		}
+		if !inList[[2]int{1, k}] {
+			findings = append(findings, lintFinding{lintError, "elem", elemName(k),
+				fmt.Sprintf("not listed in HasElems of its column %s", model.Cols[elem.InCol].Name)})
+		}
+	}
+
+	return findings, true
+}
+
+//==============================================================================
+
+// validateModel returns the findings of the validation of the model, errors
+// first, each group in the order of the checks:
+//   - elements and HasElems lists which do not agree (error)
+//   - NaN or infinite coefficients, and NaN limits or bounds (error)
+//   - lower limits or bounds above upper ones (error)
+//   - empty rows whose limits exclude zero (error)
+//   - duplicate row names, column names, or elements in the same row and
+//     column (error)
+//   - integer columns with fractional bounds (warning)
+//   - names longer than 8 characters, which do not fit in a fixed-format MPS
+//     file (warning)
+func validateModel(model lpModel) []lintFinding {
+	var findings []lintFinding  // findings made
+
+	add := func(severity, item, name, format string, args ...interface{}) {
+		findings = append(findings, lintFinding{severity, item, name, fmt.Sprintf(format, args...)})
+	}
+
+	linkFindings, linksValid := validateLinks(model)
+	findings = append(findings, linkFindings...)
+
+	if linksValid {
+		for _, elem := range model.Elems {
+			if isNaNOrInf(elem.Value) {
+				add(lintError, "row", model.Rows[elem.InRow].Name, "coefficient %g in column %s",
+					elem.Value, model.Cols[elem.InCol].Name)
+			}
+		}
+	}
+
+	for i, row := range model.Rows {
+		if math.IsNaN(row.RHSlo) || math.IsNaN(row.RHSup) {
+			add(lintError, "row", row.Name, "limits [%g, %g] are not numbers", row.RHSlo, row.RHSup)
+			continue
+		}
+		if row.RHSlo > row.RHSup {
+			add(lintError, "row", row.Name, "lower limit %g is above upper limit %g", row.RHSlo, row.RHSup)
+		}
+		if i == model.ObjRow || isFreeRow(row) || !linksValid {
+			continue
+		}
+
+		empty := true
+		for _, k := range row.HasElems {
+			if k >= 0 && k < len(model.Elems) && model.Elems[k].Value != 0 {
+				empty = false
+				break
+			}
+		}
+		if empty && (row.RHSlo > lintTol || row.RHSup < -lintTol) {
+			add(lintError, "row", row.Name, "row of type %s is empty, so cannot meet its limits [%g, %g]",
+				row.Type, row.RHSlo, row.RHSup)
+		}
+	}
+
+	for _, col := range model.Cols {
+		if math.IsNaN(col.BndLo) || math.IsNaN(col.BndUp) {
+			add(lintError, "col", col.Name, "bounds [%g, %g] are not numbers", col.BndLo, col.BndUp)
+			continue
+		}
+		if col.BndLo > col.BndUp {
+			add(lintError, "col", col.Name, "lower bound %g is above upper bound %g", col.BndLo, col.BndUp)
+		}
+	}
+
+	rowSeen := make(map[string]bool)
+	for _, row := range model.Rows {
+		if rowSeen[row.Name] {
+			add(lintError, "row", row.Name, "name is used by more than one row")
+		}
+		rowSeen[row.Name] = true
+	}
+	colSeen := make(map[string]bool)
+	for _, col := range model.Cols {
+		if colSeen[col.Name] {
+			add(lintError, "col", col.Name, "name is used by more than one column")
+		}
+		colSeen[col.Name] = true
+	}
+	if linksValid {
+		elemSeen := make(map[[2]int]int)
+		for k, elem := range model.Elems {
+			key := [2]int{elem.InRow, elem.InCol}
+			if first, ok := elemSeen[key]; ok {
+				add(lintError, "elem", "#" + strconv.Itoa(k), "row %s and column %s already hold element #%d",
+					model.Rows[elem.InRow].Name, model.Cols[elem.InCol].Name, first)
+				continue
+			}
+			elemSeen[key] = k
+		}
+	}
+
+	for _, col := range model.Cols {
+		if !isIntCol(col) {
+			continue
+		}
+		for _, bound := range []float64{col.BndLo, col.BndUp} {
+			if !isPlinfy(bound) && !isNeginf(bound) && math.Abs(bound - math.Round(bound)) > lintTol {
+				add(lintWarning, "col", col.Name, "integer column has fractional bounds [%g, %g]",
+					col.BndLo, col.BndUp)
+				break
+			}
+		}
+	}
+
+	if len(model.Name) > mpsNameLen {
+		add(lintWarning, "model", model.Name, "name is longer than %d characters", mpsNameLen)
+	}
+	for _, row := range model.Rows {
+		if len(row.Name) > mpsNameLen {
+			add(lintWarning, "row", row.Name, "name is longer than %d characters", mpsNameLen)
+		}
+	}
+	for _, col := range model.Cols {
+		if len(col.Name) > mpsNameLen {
+			add(lintWarning, "col", col.Name, "name is longer than %d characters", mpsNameLen)
+		}
+	}
+
+	sort.SliceStable(findings, func(a, b int) bool {
+		return findings[a].Severity == lintError && findings[b].Severity != lintError
+	})
+
+	return findings
+}
+
+//==============================================================================
+
+// countFindings returns the numbers of errors and warnings among the findings.
+func countFindings(findings []lintFinding) (int, int) {
+	var numErrors, numWarnings int  // findings of each severity
+
+	for _, finding := range findings {
+		if finding.Severity == lintError {
+			numErrors++
+		} else {
+			numWarnings++
+		}
+	}
+
+	return numErrors, numWarnings
+}
+
+//==============================================================================
+
+// printFindings prints the findings, leaving out the warnings unless
+// showWarnings is set, and pausing every pauseAfter lines. The function returns
+// no values.
+func printFindings(findings []lintFinding, showWarnings bool) {
+	var userString string  // input provided by user
+	var counter    int     // lines printed since the last pause
+
+	for _, finding := range findings {
+		if finding.Severity != lintError && !showWarnings {
+			continue
+		}
+		fmt.Printf("%-8s %-5s %-16s %s\n", finding.Severity, finding.Item, finding.Name, finding.Message)
+		counter++
+		if counter == pauseAfter {
+			counter    = 0
+			userString = ""
+			fmt.Printf("\nPAUSED... <CR> continue, any key to quit: ")
+			fmt.Scanln(&userString)
+			if userString != "" {
+				break
+			}
+		}
+	}
+
+}
+
+//==============================================================================
+
+// wpPreSolveCheck validates the model held in the lpo data structures before a
+// solve, listing the errors found and counting the warnings. If there are
+// errors, the user decides whether to solve anyway.
+// If the solve should not go ahead, function returns an error.
+func wpPreSolveCheck() error {
+	var userString string  // input provided by user
+
+	findings := validateModel(saveModel())
+	numErrors, numWarnings := countFindings(findings)
+	if numErrors == 0 {
+		if numWarnings > 0 {
+			fmt.Printf("Model check: %d warnings, use the validate tool to list them.\n", numWarnings)
+		}
+		return nil
+	}
+
+	fmt.Printf("Model check: %d errors and %d warnings.\n", numErrors, numWarnings)
+	printFindings(findings, false)
+
+	fmt.Printf("Solve the model anyway [Y|N]: ")
+	fmt.Scanln(&userString)
+	if userString != "y" && userString != "Y" {
+		return errors.Errorf("model check found %d errors", numErrors)
+	}
+
+	return nil
+}
+
+//==============================================================================
+
+// wpValidate validates the model held in the lpo data structures, or read from
+// the MPS file given on the command line or at the prompt, and lists every
+// error and warning found with the row, column or element concerned.
+// In case of failure, function returns an error.
+func wpValidate() error {
+	var model lpModel  // model validated
+	var err   error    // error returned by functions called
+
+	fileName := promptArg(0, "Enter MPS file name or <CR> to use data structures: ")
+	if fileName != "" {
+		if custEnvOn {
+			fileName = dSrcDev + fileName + fExtension
+		}
+		if model, err = readModelFile(fileName); err != nil {
+			return errors.Wrap(err, "wpValidate failed")
+		}
+	} else {
+		if len(lpo.Rows) == 0 || len(lpo.Cols) == 0 {
+			return errors.New("wpValidate failed, model not defined")
+		}
+		model = saveModel()
+	}
+
+	findings := validateModel(model)
+	numErrors, numWarnings := countFindings(findings)
+	fmt.Printf("Model '%s': %d rows, %d columns, %d elements, %d errors, %d warnings.\n",
+		model.Name, len(model.Rows), len(model.Cols), len(model.Elems), numErrors, numWarnings)
+	printFindings(findings, true)
+
+	return nil
+}
@@ -0,0 +1,249 @@
+// This file contains the independent verification of solutions returned by the
+// solvers against the original, unreduced, model.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Tolerance used when solutions are verified automatically after a solve.
+
+var verifyTol float64 = 1.0e-6
+
+// Number of worst violations listed in a verification report.
+
+const verifyWorst = 10
+
+// violation describes a row, bound, or integrality requirement which the
+// solution does not satisfy exactly.
+type violation struct {
+	Kind    string   // "row", "bound" or "integer"
+	Name    string   // name of the row or column
+	Value   float64  // row activity or column value
+	Lo      float64  // lower limit on the value
+	Up      float64  // upper limit on the value
+	Abs     float64  // absolute violation
+	Rel     float64  // violation relative to the limit violated
+}
+
+// verifyReport holds the results of verifying a solution.
+type verifyReport struct {
+	Tol      float64      // tolerance applied
+	Checked  int          // number of rows and columns checked
+	Missing  []string     // columns of the model with no value in the solution
+	MaxAbs   float64      // largest absolute violation
+	MaxRel   float64      // largest relative violation
+	Failed   int          // number of violations above tolerance
+	Worst    []violation  // largest violations, in decreasing order
+	ObjCalc  float64      // objective value recomputed from the solution
+	ObjSoln  float64      // objective value reported by the solver
+	ObjOk    bool         // recomputed and reported values agree
+	Pass     bool         // solution satisfies the model within tolerance
+}
+
+//==============================================================================
+
+// calcViolation returns the absolute and relative amount by which the value
+// lies outside the limits, ignoring infinite limits. The relative violation is
+// scaled by the magnitude of the limit violated, if greater than one.
+func calcViolation(value, lo, up float64) (float64, float64) {
+
+	if !isNeginf(lo) && value < lo {
+		return lo - value, (lo - value) / math.Max(1, math.Abs(lo))
+	}
+
+	if !isPlinfy(up) && value > up {
+		return value - up, (value - up) / math.Max(1, math.Abs(up))
+	}
+
+	return 0, 0
+}
+
+//==============================================================================
+
+// verifySolution checks the solution against every row, column bound and
+// integrality requirement of the model, in the same way as CalcLhs and
+// CalcConViolation do for a single row, and recomputes the objective value.
+// Columns are matched to the solution by name, and a column missing from the
+// solution is taken to be zero and reported.
+func verifySolution(model lpModel, soln lpo.PsSoln, tol float64) verifyReport {
+	var report verifyReport  // results of verification
+	var viols  []violation   // all non-zero violations found
+	var x      []float64     // solution value of each column
+
+	report.Tol     = tol
+	report.ObjSoln = soln.ObjVal
+
+	x = make([]float64, len(model.Cols))
+	for j, col := range model.Cols {
+		if varb, ok := soln.VarMap[col.Name]; ok {
+			x[j] = varb.Value
+		} else {
+			report.Missing = append(report.Missing, col.Name)
+		}
+	}
+
+	add := func(v violation) {
+		if v.Abs > 0 {
+			viols = append(viols, v)
+		}
+		report.Checked++
+	}
+
+	for i, row := range model.Rows {
+		if isFreeRow(row) {
+			continue
+		}
+		v := violation{Kind: "row", Name: row.Name, Value: rowActivity(model, i, x),
+			Lo: row.RHSlo, Up: row.RHSup}
+		v.Abs, v.Rel = calcViolation(v.Value, v.Lo, v.Up)
+		add(v)
+	}
+
+	for j, col := range model.Cols {
+		v := violation{Kind: "bound", Name: col.Name, Value: x[j], Lo: col.BndLo, Up: col.BndUp}
+		v.Abs, v.Rel = calcViolation(v.Value, v.Lo, v.Up)
+		add(v)
+
+		if isIntCol(col) {
+			nearest := math.Floor(x[j] + 0.5)
+			v = violation{Kind: "integer", Name: col.Name, Value: x[j], Lo: nearest, Up: nearest}
+			v.Abs = math.Abs(x[j] - nearest)
+			v.Rel = v.Abs
+			add(v)
+		}
+	}
+
+	sort.Slice(viols, func(a, b int) bool { return viols[a].Abs > viols[b].Abs })
+
+	for _, v := range viols {
+		report.MaxAbs = math.Max(report.MaxAbs, v.Abs)
+		report.MaxRel = math.Max(report.MaxRel, v.Rel)
+		if v.Rel > tol {
+			report.Failed++
+		}
+	}
+
+	if len(viols) > verifyWorst {
+		viols = viols[:verifyWorst]
+	}
+	report.Worst = viols
+
+	for j, coef := range objCoefs(model) {
+		report.ObjCalc += coef * x[j]
+	}
+	report.ObjOk = math.Abs(report.ObjCalc - report.ObjSoln) <=
+		tol * math.Max(1, math.Abs(report.ObjSoln))
+
+	report.Pass = report.Failed == 0 && len(report.Missing) == 0 && report.ObjOk
+
+	return report
+}
+
+//==============================================================================
+
+// printVerify prints the verification report. If showWorst is false, the worst
+// violations are only listed when verification failed. The function returns no
+// values.
+func printVerify(report verifyReport, showWorst bool) {
+
+	result := "PASSED"
+	if !report.Pass {
+		result = "FAILED"
+	}
+
+	fmt.Printf("\nVerification %s with tolerance %g (%d rows, bounds and integers checked).\n",
+		result, report.Tol, report.Checked)
+	fmt.Printf("Max absolute violation = %e, max relative violation = %e, %d above tolerance.\n",
+		report.MaxAbs, report.MaxRel, report.Failed)
+	fmt.Printf("Objective recomputed = %f, reported = %f, difference = %e.\n",
+		report.ObjCalc, report.ObjSoln, report.ObjCalc - report.ObjSoln)
+
+	if len(report.Missing) > 0 {
+		fmt.Printf("WARNING: %d columns have no value in the solution, e.g. '%s'.\n",
+			len(report.Missing), report.Missing[0])
+	}
+
+	if len(report.Worst) == 0 || (report.Pass && !showWorst) {
+		return
+	}
+
+	fmt.Printf("\nWorst violations:\n")
+	fmt.Printf("%-8s %-16s %15s %15s %15s %15s %15s\n", "KIND", "NAME", "VALUE", "LOWER",
+		"UPPER", "ABS VIOL", "REL VIOL")
+	for _, v := range report.Worst {
+		fmt.Printf("%-8s %-16s %15e %15e %15e %15e %15e\n", v.Kind, v.Name, v.Value,
+			v.Lo, v.Up, v.Abs, v.Rel)
+	}
+
+}
+
+//==============================================================================
+
+// wpAutoVerify verifies the solution just obtained by the solve problem
+// command. The original model is read again from fileName if the solve read it
+// from a file, and is otherwise the copy of the data structures saved before
+// the solve. The function returns no values.
+func wpAutoVerify(fileName string, model lpModel) {
+	var err error  // error returned by functions called
+
+	if fileName != "" {
+		if model, err = readModelFile(fileName); err != nil {
+			fmt.Printf("WARNING: Solution not verified: %s\n", err)
+			return
+		}
+	}
+
+	printVerify(verifySolution(model, psResult, verifyTol), false)
+}
+
+//==============================================================================
+
+// wpVerify verifies the current lpo solution against the original model, which
+// is read from an MPS file or, if no file is given, taken from the lpo data
+// structures. The user may set the tolerance, which also becomes the tolerance
+// used for automatic verification after each solve.
+// In case of failure, function returns an error.
+func wpVerify() error {
+	var fileName   string    // MPS file holding the original model
+	var userString string    // input provided by user
+	var model      lpModel   // original model
+	var err        error     // error returned by functions called
+
+	if len(psResult.VarMap) == 0 {
+		return errors.New("wpVerify failed, no solution available")
+	}
+
+	fileName = promptArg(0, "Enter original MPS file name or <CR> to use data structures: ")
+	if fileName != "" {
+		if custEnvOn {
+			fileName = dSrcDev + fileName + fExtension
+		}
+		if model, err = readModelFile(fileName); err != nil {
+			return errors.Wrap(err, "wpVerify failed")
+		}
+	} else {
+		model = saveModel()
+	}
+
+	fmt.Printf("Enter tolerance or <CR> for %g: ", verifyTol)
+	fmt.Scanln(&userString)
+	if userString != "" {
+		tol, err := strconv.ParseFloat(userString, 64)
+		if err != nil || tol < 0 {
+			return errors.Errorf("'%s' is not a valid tolerance.", userString)
+		}
+		verifyTol = tol
+	}
+
+	printVerify(verifySolution(model, psResult, verifyTol), true)
+
+	return nil
+}
@@ -0,0 +1,329 @@
+// This file contains functions for running solves in worker processes. Since
+// the lpo package keeps the model in package global variables, concurrent solves
+// must each run in a separate copy of this executable.
+// 01 - Oct. 18, 2026   First version
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-opt/lpo"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Command-line argument which starts the executable as a worker. It is followed
+// by the name of the job file and the name of the result file.
+
+const workerArg = "-worker"
+
+// workerJob is the job passed to a worker process. The model is always read
+// from the MPS file named in the control structure.
+type workerJob struct {
+	UseCoin bool        // solve with Coin-OR instead of Cplex
+	PsCtrl  lpo.PsCtrl  // control structure passed to the solver
+}
+
+// workerResult is the result written by a worker process.
+type workerResult struct {
+	PsSoln  lpo.PsSoln     // solution returned by the solver
+	Err     string         // error returned by the solver, empty if none
+	Elapsed time.Duration  // time taken by the solver
+	Rows    int            // number of rows in the model read
+	Cols    int            // number of columns in the model read
+	Elems   int            // number of elements in the model read
+}
+
+// raceEntry holds the outcome for one solver taking part in a race.
+type raceEntry struct {
+	Name    string         // name of the solver
+	UseCoin bool           // solver is Coin-OR rather than Cplex
+	Status  string         // outcome of the race for this solver
+	Elapsed time.Duration  // time from start until finished or cancelled
+	Result  workerResult   // result reported by the worker
+}
+
+//==============================================================================
+
+// isWorker determines whether the executable was started as a worker process.
+// It accepts no arguments and returns true if it was.
+func isWorker() bool {
+
+	return len(os.Args) > 1 && os.Args[1] == workerArg
+}
+
+//==============================================================================
+
+// runWorker executes the job file named on the command line and writes the
+// result file. Everything the solver prints goes to this process' output, which
+// the parent discards. It accepts no arguments and returns the exit status.
+func runWorker() int {
+	var job     workerJob     // job to be executed
+	var result  workerResult  // result of the job
+
+	if len(os.Args) != 4 {
+		fmt.Fprintf(os.Stderr, "usage: %s %s jobFile resultFile\n", os.Args[0], workerArg)
+		return 2
+	}
+
+	data, err := ioutil.ReadFile(os.Args[2])
+	if err == nil {
+		err = json.Unmarshal(data, &job)
+	}
+
+	// The model is read here rather than by the solver so that its size before
+	// reduction can be reported.
+	if err != nil {
+		result.Err = errors.Wrap(err, "Failed to read worker job").Error()
+	} else if err = readMpsSense(job.PsCtrl.FileInMps); err != nil {
+		result.Err = errors.Wrap(err, "Failed to read model").Error()
+	} else {
+		result.Rows  = len(lpo.Rows)
+		result.Cols  = len(lpo.Cols)
+		result.Elems = len(lpo.Elems)
+		job.PsCtrl.FileInMps = ""
+
+		startTime := time.Now()
+		if err = callSolver(job.UseCoin, job.PsCtrl, &result.PsSoln); err != nil {
+			result.Err = err.Error()
+		}
+		result.Elapsed = time.Since(startTime)
+	}
+
+	if data, err = json.Marshal(result); err != nil {
+		data, _ = json.Marshal(workerResult{Err: err.Error(), Elapsed: result.Elapsed})
+	}
+
+	if err = ioutil.WriteFile(os.Args[3], data, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+//==============================================================================
+
+// startWorker writes the job to a file in the directory specified and starts a
+// worker process to execute it. The worker's output is discarded. The function
+// returns the running command and the name of the file the worker will write its
+// result to. In case of failure, function returns an error.
+func startWorker(job workerJob, workDir string, tag string) (*exec.Cmd, string, error) {
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Cannot locate executable for worker")
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "Cannot encode worker job")
+	}
+
+	jobFile    := filepath.Join(workDir, "job_"    + tag + ".json")
+	resultFile := filepath.Join(workDir, "result_" + tag + ".json")
+
+	if err = ioutil.WriteFile(jobFile, data, 0644); err != nil {
+		return nil, "", errors.Wrapf(err, "Cannot write worker job %s", jobFile)
+	}
+
+	cmd := exec.Command(exe, workerArg, jobFile, resultFile)
+	if err = cmd.Start(); err != nil {
+		return nil, "", errors.Wrap(err, "Cannot start worker")
+	}
+
+	return cmd, resultFile, nil
+}
+
+//==============================================================================
+
+// waitWorker waits for a worker process to exit and reads its result file. If
+// the context is done first, the worker and its solver are killed and the error
+// for the aborted solve is returned. In case of failure, function returns an error.
+func waitWorker(ctx context.Context, cmd *exec.Cmd, resultFile string) (workerResult, error) {
+	var result workerResult  // result read from the result file
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	select {
+
+	case err := <-exited:
+		data, readErr := ioutil.ReadFile(resultFile)
+		if readErr != nil {
+			if err == nil {
+				err = readErr
+			}
+			return result, errors.Wrap(err, "Worker did not produce a result")
+		}
+		if err = json.Unmarshal(data, &result); err != nil {
+			return result, errors.Wrap(err, "Cannot decode worker result")
+		}
+		return result, nil
+
+	case <-ctx.Done():
+		killProcTree(cmd.Process)
+		<-exited
+		return result, abortError(ctx)
+
+	} // end select on worker completion
+
+}
+
+//==============================================================================
+
+// modelFileForWorkers returns the name of an MPS file from which worker
+// processes can read the model. If the solve reads an MPS file anyway, its name
+// is returned; otherwise the model in the lpo data structures is written to the
+// working directory. In case of failure, function returns an error.
+func modelFileForWorkers(psCtrl lpo.PsCtrl, workDir string) (string, error) {
+
+	if psCtrl.FileInMps != "" {
+		return psCtrl.FileInMps, nil
+	}
+
+	fileName := filepath.Join(workDir, "model.mps")
+	if err := writeMpsSense(fileName); err != nil {
+		return "", errors.Wrap(err, "Cannot write model for workers")
+	}
+
+	return fileName, nil
+}
+
+//==============================================================================
+
+// wpRaceSolvers solves the problem with every solver at the same time, each in
+// its own worker process reading its own copy of the model and applying the
+// reductions in psCtrl. The first solver to return a solution wins and the
+// others are cancelled. The winning solution is returned in psSoln and the
+// outcome for each solver is printed. Solution and PSOP files are not written,
+// since all solvers would write to the same file.
+// In case of failure, function returns an error.
+func wpRaceSolvers(timeLimit time.Duration, psCtrl lpo.PsCtrl, psSoln *lpo.PsSoln) error {
+	var entries []raceEntry  // outcome for each solver
+	var winner   int         // index of winning entry, -1 if none
+
+	if err := checkPendingSolve(); err != nil {
+		return err
+	}
+
+	workDir, err := ioutil.TempDir("", "runopt_race")
+	if err != nil {
+		return errors.Wrap(err, "Cannot create directory for race")
+	}
+	defer os.RemoveAll(workDir)
+
+	if psCtrl.FileInMps, err = modelFileForWorkers(psCtrl, workDir); err != nil {
+		return err
+	}
+	psCtrl.FileOutSoln    = ""
+	psCtrl.FileOutPsop    = ""
+	psCtrl.FileOutMpsRdcd = ""
+
+	entries = []raceEntry{
+		{Name: "Coin-OR", UseCoin: true},
+		{Name: "Cplex",   UseCoin: false},
+	}
+
+	// Every solver gets its own context so that the losers can be cancelled
+	// individually once a winner is known.
+	ctx, endSolve := beginSolve(timeLimit)
+	defer endSolve()
+
+	type finish struct {
+		index  int
+		result workerResult
+		err    error
+	}
+	finished  := make(chan finish, len(entries))
+	cancels   := make([]context.CancelFunc, len(entries))
+	startTime := time.Now()
+
+	for i := range entries {
+		var solverCtx context.Context
+		solverCtx, cancels[i] = context.WithCancel(ctx)
+
+		job := workerJob{UseCoin: entries[i].UseCoin, PsCtrl: psCtrl}
+		cmd, resultFile, err := startWorker(job, workDir, fmt.Sprintf("%d", i))
+		if err != nil {
+			finished <- finish{index: i, err: err}
+			continue
+		}
+
+		go func(i int) {
+			result, err := waitWorker(solverCtx, cmd, resultFile)
+			finished <- finish{index: i, result: result, err: err}
+		}(i)
+	}
+
+	winner = -1
+	for range entries {
+		f := <-finished
+		entries[f.index].Elapsed = time.Since(startTime)
+		entries[f.index].Result  = f.result
+
+		switch {
+		case f.err == errInterrupted && winner >= 0:
+			// Cancelling the other solvers once there is a winner interrupts them.
+			entries[f.index].Status = "cancelled"
+		case f.err != nil:
+			entries[f.index].Status = "failed: " + f.err.Error()
+		case f.result.Err != "":
+			entries[f.index].Status = "failed: " + f.result.Err
+		case winner >= 0:
+			entries[f.index].Status = "finished second"
+		default:
+			entries[f.index].Status = "winner"
+			winner = f.index
+			for j := range cancels {
+				if j != winner {
+					cancels[j]()
+				}
+			}
+		}
+	}
+
+	for i := range cancels {
+		cancels[i]()
+	}
+
+	fmt.Printf("\nRace results:\n")
+	fmt.Printf("%-10s %12s   %s\n", "SOLVER", "ELAPSED", "STATUS")
+	for _, entry := range entries {
+		fmt.Printf("%-10s %12s   %s\n", entry.Name,
+			entry.Elapsed.Round(time.Millisecond), firstLine(entry.Status))
+	}
+
+	if winner < 0 {
+		if ctx.Err() != nil {
+			return abortError(ctx)
+		}
+		return errors.New("No solver returned a solution")
+	}
+
+	fmt.Printf("\n%s won the race, solver time %s.\n", entries[winner].Name,
+		entries[winner].Result.Elapsed.Round(time.Millisecond))
+	*psSoln = entries[winner].Result.PsSoln
+
+	return nil
+}
+
+//==============================================================================
+
+// firstLine returns the first line of a possibly multi-line message.
+func firstLine(message string) string {
+
+	if i := strings.IndexByte(message, '\n'); i >= 0 {
+		return message[:i]
+	}
+	return message
+}